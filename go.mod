@@ -1,5 +1,16 @@
 module github.com/tmc/json-to-struct
 
-go 1.14
+go 1.25
 
-require github.com/google/go-cmp v0.4.0
+require (
+	github.com/gofrs/uuid/v5 v5.5.1
+	github.com/google/go-cmp v0.4.0
+	github.com/google/uuid v1.6.0
+	github.com/shopspring/decimal v1.4.0
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+require (
+	golang.org/x/xerrors v0.0.0-20191204190536-9bdfabe68543 // indirect
+	gopkg.in/check.v1 v0.0.0-20161208181325-20d25e280405 // indirect
+)
@@ -0,0 +1,754 @@
+package jsonstruct
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// records holds a sequence of top-level JSON documents, such as the lines of
+// an NDJSON stream.
+type records []interface{}
+
+// gzipMagic are the first bytes of a gzip stream.
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// gunzipInput returns a reader decompressing input if it starts with the gzip
+// magic bytes, and a reader of input as is otherwise.
+func gunzipInput(input io.Reader) (io.Reader, error) {
+	br := bufio.NewReader(input)
+	magic, err := br.Peek(len(gzipMagic))
+	if err != nil || !bytes.Equal(magic, gzipMagic) {
+		// short inputs are left for the decoder to report.
+		return br, nil
+	}
+	return gzip.NewReader(br)
+}
+
+// readInput decompresses and decodes input as decodeInput does, selecting
+// the records matched by cfg.JSONPath. A copy of input is written to
+// cfg.Sample, and the key order of its first record is returned if
+// cfg.FieldOrder needs it. The files of a Files input are decoded one at a
+// time and their records merged.
+func readInput(input io.Reader, cfg *Config) (interface{}, *keyOrder, error) {
+	if files, ok := input.(*Files); ok {
+		return files.decode(cfg)
+	}
+	input, err := gunzipInput(input)
+	if err != nil {
+		return nil, nil, err
+	}
+	var keys *keyOrder
+	if cfg.FieldOrder == "first-record" || cfg.Sample != nil {
+		data, err := ioutil.ReadAll(input)
+		if err != nil {
+			return nil, nil, err
+		}
+		if cfg.FieldOrder == "first-record" {
+			keys = inputKeyOrder(data, cfg.Format)
+		}
+		if cfg.Sample != nil {
+			if _, err := cfg.Sample.Write(data); err != nil {
+				return nil, nil, err
+			}
+		}
+		input = bytes.NewReader(data)
+	}
+	iresult, err := decodeInput(input, cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+	if cfg.JSONPath != "" {
+		if iresult, err = selectRecords(iresult, cfg.JSONPath); err != nil {
+			return nil, nil, err
+		}
+		keys = keys.selectPath(cfg.JSONPath)
+	}
+	return iresult, keys, nil
+}
+
+// decodeInput decodes input as a single JSON document. If input holds more
+// than one document it is treated as newline-delimited JSON (NDJSON) and the
+// decoded lines are returned as records. With a SampleLimit, JSON input is
+// only read up to the last record kept.
+func decodeInput(input io.Reader, cfg *Config) (interface{}, error) {
+	if cfg.SampleLimit > 0 && (cfg.Format == "" || cfg.Format == "json") {
+		return decodeSample(input, cfg)
+	}
+	data, err := ioutil.ReadAll(input)
+	if err != nil {
+		return nil, err
+	}
+	switch cfg.Format {
+	case "query", "form":
+		return decodeQuery(data, cfg.Format == "query")
+	case "yaml":
+		return decodeYAML(data)
+	}
+	return decodeJSON(data, cfg)
+}
+
+// decodeJSON decodes data as decodeInput does JSON input.
+func decodeJSON(data []byte, cfg *Config) (interface{}, error) {
+	var result interface{}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if cfg.UseJSONNumber {
+		dec.UseNumber()
+	}
+	err := dec.Decode(&result)
+	if err == io.EOF {
+		return nil, err
+	}
+	end := dec.InputOffset()
+	if err == nil {
+		if _, err := dec.Token(); err == io.EOF {
+			return result, nil
+		}
+	}
+	if cfg.NoNDJSON {
+		if err == nil {
+			err = fmt.Errorf("unexpected data after the JSON document")
+			trailing := bytes.TrimLeft(data[end:], " \t\r\n")
+			return nil, locateError(data, int64(len(data)-len(trailing)), err)
+		}
+		return nil, locateError(data, syntaxErrorOffset(err, dec), err)
+	}
+	lines, ndErr := decodeNDJSON(data, cfg)
+	if ndErr != nil {
+		// report the original error if the input doesn't look like NDJSON either.
+		if err != nil {
+			return nil, locateError(data, syntaxErrorOffset(err, dec), err)
+		}
+		return nil, ndErr
+	}
+	return lines, nil
+}
+
+// skipSpace discards the leading white space of br and returns the next
+// byte without consuming it, or 0 at the end of the input.
+func skipSpace(br *bufio.Reader) byte {
+	for {
+		c, err := br.ReadByte()
+		if err != nil {
+			return 0
+		}
+		if c != ' ' && c != '\t' && c != '\r' && c != '\n' {
+			br.UnreadByte()
+			return c
+		}
+	}
+}
+
+// decodeArrayPrefix decodes the first cfg.SampleLimit elements of the JSON
+// array input starts with, reading no further than the last of them. If the
+// array has fewer elements, the documents following it are decoded as NDJSON
+// lines until the limit is reached.
+func decodeArrayPrefix(input io.Reader, cfg *Config) (interface{}, error) {
+	dec := json.NewDecoder(input)
+	if cfg.UseJSONNumber {
+		dec.UseNumber()
+	}
+	if _, err := dec.Token(); err != nil {
+		return nil, err
+	}
+	var result []interface{}
+	for len(result) < cfg.SampleLimit && dec.More() {
+		var elem interface{}
+		if err := dec.Decode(&elem); err != nil {
+			return nil, err
+		}
+		result = append(result, elem)
+	}
+	if len(result) == cfg.SampleLimit {
+		return result, nil
+	}
+	if _, err := dec.Token(); err != nil {
+		return nil, err
+	}
+	lines := records(result)
+	for len(lines) < cfg.SampleLimit {
+		var record interface{}
+		if err := dec.Decode(&record); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		switch v := record.(type) {
+		case map[string]interface{}:
+			lines = append(lines, v)
+		case []interface{}:
+			lines = append(lines, v...)
+		default:
+			return nil, fmt.Errorf("unexpected type: %T", record)
+		}
+	}
+	if len(lines) == len(result) {
+		// the array was the only document.
+		return result, nil
+	}
+	if len(lines) > cfg.SampleLimit {
+		lines = lines[:cfg.SampleLimit]
+	}
+	return lines, nil
+}
+
+// decodeSample decodes JSON input as decodeInput does, reading no further
+// than the first cfg.SampleLimit records. A decoder over the stream tells a
+// single document, which is read whole, from NDJSON, which is read line by
+// line.
+func decodeSample(input io.Reader, cfg *Config) (interface{}, error) {
+	br := bufio.NewReader(input)
+	if skipSpace(br) == '[' {
+		return decodeArrayPrefix(br, cfg)
+	}
+	var head bytes.Buffer
+	dec := json.NewDecoder(io.TeeReader(br, &head))
+	if cfg.UseJSONNumber {
+		dec.UseNumber()
+	}
+	var result interface{}
+	err := dec.Decode(&result)
+	if err == io.EOF {
+		return nil, err
+	}
+	if err == nil {
+		if _, err := dec.Token(); err == io.EOF {
+			return result, nil
+		}
+	}
+	if cfg.NoNDJSON {
+		// read the rest to report the error as decodeJSON does.
+		rest, err := ioutil.ReadAll(br)
+		if err != nil {
+			return nil, err
+		}
+		return decodeJSON(append(head.Bytes(), rest...), cfg)
+	}
+	data, err := readRecordLines(head.Bytes(), br, cfg.SampleLimit)
+	if err != nil {
+		return nil, err
+	}
+	result, err = decodeJSON(data, cfg)
+	if lines, ok := result.(records); ok && len(lines) > cfg.SampleLimit {
+		result = lines[:cfg.SampleLimit]
+	}
+	return result, err
+}
+
+// readRecordLines reads NDJSON lines from br, following the start of the
+// stream already read into head, until they hold limit records, and returns
+// them all. Each object of an array line counts as a record.
+func readRecordLines(head []byte, br *bufio.Reader, limit int) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.Write(head)
+	n := 0
+	partial := head
+	if i := bytes.LastIndexByte(head, '\n'); i >= 0 {
+		for _, line := range bytes.Split(head[:i], []byte("\n")) {
+			n += lineRecords(line)
+		}
+		partial = head[i+1:]
+	}
+	partial = append([]byte(nil), partial...)
+	for n < limit {
+		line, err := br.ReadBytes('\n')
+		buf.Write(line)
+		if err != nil && err != io.EOF {
+			return nil, err
+		}
+		n += lineRecords(append(partial, line...))
+		partial = nil
+		if err == io.EOF {
+			break
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+// lineRecords returns the number of records the NDJSON line holds, or none
+// if it fails to parse. Scalars are counted as records.
+func lineRecords(line []byte) int {
+	records, err := decodeLine(bytes.TrimSpace(line), &Config{})
+	if err != nil {
+		return 0
+	}
+	return len(records)
+}
+
+// decodeLine decodes the stream of JSON values in the NDJSON line, returning
+// the records they hold as decodeNDJSON does.
+func decodeLine(line []byte, cfg *Config) ([]interface{}, error) {
+	dec := json.NewDecoder(bytes.NewReader(line))
+	if cfg.UseJSONNumber {
+		dec.UseNumber()
+	}
+	var result []interface{}
+	for {
+		var v interface{}
+		if err := dec.Decode(&v); err == io.EOF {
+			return result, nil
+		} else if err != nil {
+			return nil, err
+		}
+		if elems, ok := v.([]interface{}); ok {
+			result = append(result, elems...)
+		} else {
+			result = append(result, v)
+		}
+	}
+}
+
+// syntaxErrorOffset returns the input offset of the byte at which decoding
+// failed with err.
+func syntaxErrorOffset(err error, dec *json.Decoder) int64 {
+	if err, ok := err.(*json.SyntaxError); ok && err.Offset > 0 {
+		// Offset counts the offending byte.
+		return err.Offset - 1
+	}
+	return dec.InputOffset()
+}
+
+// locateError annotates err with the line and column of offset in data.
+func locateError(data []byte, offset int64, err error) error {
+	if offset > int64(len(data)) {
+		offset = int64(len(data))
+	}
+	before := data[:offset]
+	line := bytes.Count(before, []byte("\n")) + 1
+	col := len(before) - bytes.LastIndexByte(before, '\n')
+	return fmt.Errorf("line %d, column %d: %w", line, col, err)
+}
+
+// decodeNDJSON decodes each non-blank line of data as a stream of JSON
+// values, such as {"a":1} or {"a":1}{"a":2}. Each value is a record, except
+// that each element of an array is a record, as it is for a top-level array.
+// Records are objects, unless no line holds one, in which case they are the
+// scalars of a stream such as "1\n2\n". Lines that fail to parse or hold
+// other records are skipped, counted and reported to cfg.Log, and copied to
+// cfg.BadLines if set.
+func decodeNDJSON(data []byte, cfg *Config) (records, error) {
+	type line struct {
+		n      int
+		text   []byte
+		values []interface{}
+		err    error
+	}
+	var (
+		lines   []line
+		objects bool
+	)
+	for i, text := range bytes.Split(data, []byte("\n")) {
+		text = bytes.TrimSpace(text)
+		if len(text) == 0 {
+			continue
+		}
+		values, err := decodeLine(text, cfg)
+		for _, v := range values {
+			if _, ok := v.(map[string]interface{}); ok {
+				objects = true
+			}
+		}
+		lines = append(lines, line{i + 1, text, values, err})
+	}
+	var (
+		result   records
+		bad      int
+		firstBad int
+		firstErr error
+	)
+	for _, l := range lines {
+		err := l.err
+		for _, v := range l.values {
+			if _, ok := v.(map[string]interface{}); objects && !ok && err == nil {
+				err = fmt.Errorf("unexpected type: %T", v)
+			}
+		}
+		if err != nil {
+			if bad == 0 {
+				firstBad, firstErr = l.n, err
+			}
+			bad++
+			if cfg.BadLines != nil {
+				fmt.Fprintf(cfg.BadLines, "%s\n", l.text)
+			}
+			continue
+		}
+		result = append(result, l.values...)
+	}
+	total := len(lines)
+	if len(result) == 0 {
+		return nil, fmt.Errorf("no valid NDJSON records in %d lines", total)
+	}
+	if bad > 0 && cfg.Log != nil {
+		fmt.Fprintf(cfg.Log, "skipped %d of %d NDJSON lines that failed to parse (line %d: %v)\n",
+			bad, total, firstBad, firstErr)
+	}
+	return result, nil
+}
+
+// selectRecords returns the objects in the decoded input v matched by the
+// JSONPath expression path as records. It is an error if path matches
+// nothing or anything other than an object.
+func selectRecords(v interface{}, path string) (records, error) {
+	docs := []interface{}{v}
+	if r, ok := v.(records); ok {
+		docs = r
+	}
+	var result records
+	for _, doc := range docs {
+		nodes, err := selectJSONPath(doc, path)
+		if err != nil {
+			return nil, err
+		}
+		for _, node := range nodes {
+			if _, ok := node.(map[string]interface{}); !ok {
+				return nil, fmt.Errorf("jsonpath %q matched %s, not an object", path, jsonKind(node))
+			}
+			result = append(result, node)
+		}
+	}
+	if len(result) == 0 {
+		return nil, fmt.Errorf("jsonpath %q matched nothing", path)
+	}
+	return result, nil
+}
+
+// jsonKind returns the kind of the decoded JSON value v, such as "an array".
+func jsonKind(v interface{}) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case []interface{}:
+		return "an array"
+	case string:
+		return "a string"
+	case float64, json.Number:
+		return "a number"
+	case bool:
+		return "a boolean"
+	}
+	return "an object"
+}
+
+// decodeQuery decodes each non-blank line of data as a URL-encoded query
+// string, returning a single object for one line and records otherwise. If
+// isURL is true, anything up to and including a "?" is dropped, so whole URLs
+// may be given. Values are coerced by coerceQueryValue, and repeated keys
+// become arrays.
+func decodeQuery(data []byte, isURL bool) (interface{}, error) {
+	var result records
+	for i, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if isURL {
+			if i := strings.Index(line, "?"); i >= 0 {
+				line = line[i+1:]
+			}
+		}
+		if line == "" {
+			continue
+		}
+		values, err := url.ParseQuery(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", i+1, err)
+		}
+		record := map[string]interface{}{}
+		for key, vals := range values {
+			if len(vals) == 1 {
+				record[key] = coerceQueryValue(vals[0])
+				continue
+			}
+			var arr []interface{}
+			for _, v := range vals {
+				arr = append(arr, coerceQueryValue(v))
+			}
+			record[key] = arr
+		}
+		result = append(result, record)
+	}
+	switch len(result) {
+	case 0:
+		return nil, io.EOF
+	case 1:
+		return result[0], nil
+	}
+	return result, nil
+}
+
+// decodeYAML decodes data as a stream of YAML documents, returning a single
+// value for one document and records otherwise. Values are normalized to the
+// types encoding/json decodes into.
+func decodeYAML(data []byte) (interface{}, error) {
+	var result records
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	for {
+		var doc interface{}
+		err := dec.Decode(&doc)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, normalizeYAML(doc))
+	}
+	switch len(result) {
+	case 0:
+		return nil, io.EOF
+	case 1:
+		return result[0], nil
+	}
+	return result, nil
+}
+
+// normalizeYAML converts the decoded YAML value v to the types
+// encoding/json decodes into: maps with string keys, float64 numbers and
+// strings for timestamps.
+func normalizeYAML(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			v[key] = normalizeYAML(value)
+		}
+		return v
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for key, value := range v {
+			m[fmt.Sprint(key)] = normalizeYAML(value)
+		}
+		return m
+	case []interface{}:
+		for i, value := range v {
+			v[i] = normalizeYAML(value)
+		}
+		return v
+	case int:
+		return float64(v)
+	case int64:
+		return float64(v)
+	case uint64:
+		return float64(v)
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+	}
+	return v
+}
+
+// coerceQueryValue returns v as a float64 if it is a finite number, as a
+// bool if it is "true" or "false", and unchanged otherwise. Values such as
+// "nan" and "inf", which JSON can't hold as numbers, stay strings.
+func coerceQueryValue(v string) interface{} {
+	if f, err := strconv.ParseFloat(v, 64); err == nil && !math.IsNaN(f) && !math.IsInf(f, 0) {
+		return f
+	}
+	if v == "true" || v == "false" {
+		return v == "true"
+	}
+	return v
+}
+
+// keyOrder records the order of the keys of the first JSON object seen at a
+// position in a document, and of the objects nested in it. The elements of
+// an array share the keyOrder of the array.
+type keyOrder struct {
+	keys   []string
+	fields map[string]*keyOrder
+	done   bool
+	// array is set if the value at the position was an array.
+	array bool
+}
+
+// inputKeyOrder returns the key order of the first record of data, in the
+// input format format, or nil if it can't be decoded.
+func inputKeyOrder(data []byte, format string) *keyOrder {
+	switch format {
+	case "query", "form":
+		return queryKeyOrder(data, format == "query")
+	case "yaml":
+		return yamlKeyOrder(data)
+	}
+	return decodeKeyOrder(data)
+}
+
+// decodeKeyOrder returns the key order of the first JSON value in data, or
+// nil if it can't be decoded.
+func decodeKeyOrder(data []byte) *keyOrder {
+	keys := &keyOrder{}
+	if err := keys.decode(json.NewDecoder(bytes.NewReader(data))); err != nil {
+		return nil
+	}
+	return keys
+}
+
+// decode reads the next JSON value from dec, recording the key order of its
+// objects.
+func (k *keyOrder) decode(dec *json.Decoder) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	switch tok {
+	case json.Delim('{'):
+		for dec.More() {
+			tok, err := dec.Token()
+			if err != nil {
+				return err
+			}
+			key := tok.(string)
+			if !k.done {
+				k.keys = append(k.keys, key)
+			}
+			if k.fields == nil {
+				k.fields = map[string]*keyOrder{}
+			}
+			if k.fields[key] == nil {
+				k.fields[key] = &keyOrder{}
+			}
+			if err := k.fields[key].decode(dec); err != nil {
+				return err
+			}
+		}
+		k.done = true
+	case json.Delim('['):
+		k.array = true
+		for dec.More() {
+			if err := k.decode(dec); err != nil {
+				return err
+			}
+		}
+	default:
+		return nil
+	}
+	// consume the closing delimiter.
+	_, err = dec.Token()
+	return err
+}
+
+// yamlKeyOrder returns the key order of the first YAML document in data, or
+// nil if it can't be decoded.
+func yamlKeyOrder(data []byte) *keyOrder {
+	var doc yaml.Node
+	if err := yaml.NewDecoder(bytes.NewReader(data)).Decode(&doc); err != nil {
+		return nil
+	}
+	keys := &keyOrder{}
+	keys.addYAML(&doc)
+	return keys
+}
+
+// addYAML records the key order of the mappings in the YAML node n.
+func (k *keyOrder) addYAML(n *yaml.Node) {
+	switch n.Kind {
+	case yaml.DocumentNode:
+		for _, child := range n.Content {
+			k.addYAML(child)
+		}
+	case yaml.AliasNode:
+		k.addYAML(n.Alias)
+	case yaml.SequenceNode:
+		k.array = true
+		for _, child := range n.Content {
+			k.addYAML(child)
+		}
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			key := n.Content[i].Value
+			if !k.done {
+				k.keys = append(k.keys, key)
+			}
+			if k.fields == nil {
+				k.fields = map[string]*keyOrder{}
+			}
+			if k.fields[key] == nil {
+				k.fields[key] = &keyOrder{}
+			}
+			k.fields[key].addYAML(n.Content[i+1])
+		}
+		k.done = true
+	}
+}
+
+// queryKeyOrder returns the order of the parameters of the first query string
+// in data, decoded as decodeQuery does.
+func queryKeyOrder(data []byte, isURL bool) *keyOrder {
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if i := strings.Index(line, "?"); isURL && i >= 0 {
+			line = line[i+1:]
+		}
+		if line == "" {
+			continue
+		}
+		keys := &keyOrder{done: true}
+		seen := map[string]bool{}
+		for _, param := range strings.Split(line, "&") {
+			key, err := url.QueryUnescape(strings.SplitN(param, "=", 2)[0])
+			if err != nil || key == "" || seen[key] {
+				continue
+			}
+			seen[key] = true
+			keys.keys = append(keys.keys, key)
+		}
+		return keys
+	}
+	return nil
+}
+
+// selectPath returns the key order of the values matched by the JSONPath
+// expression path, or nil if it isn't known. Like the root object's values
+// with TopLevelMap, the members matched by a wildcard take the key order of
+// the first member.
+func (k *keyOrder) selectPath(path string) *keyOrder {
+	steps, err := parseJSONPath(path)
+	if err != nil {
+		return nil
+	}
+	for _, step := range steps {
+		switch {
+		case k == nil:
+			return nil
+		case k.array && (step.wildcard || step.isIndex):
+			// the elements share the key order of the array.
+		case step.wildcard && len(k.keys) > 0:
+			k = k.field(k.keys[0])
+		case step.wildcard || step.isIndex:
+			return nil
+		default:
+			k = k.field(step.key)
+		}
+	}
+	return k
+}
+
+// position returns the index of key in the first object, or the number of
+// keys if it wasn't present.
+func (k *keyOrder) position(key string) int {
+	if k == nil {
+		return 0
+	}
+	for i, k := range k.keys {
+		if k == key {
+			return i
+		}
+	}
+	return len(k.keys)
+}
+
+// field returns the key order of the value of the field key, or nil if it
+// isn't known.
+func (k *keyOrder) field(key string) *keyOrder {
+	if k == nil {
+		return nil
+	}
+	return k.fields[key]
+}
@@ -0,0 +1,215 @@
+package jsonstruct
+
+import (
+	"fmt"
+	"go/ast"
+	"go/types"
+	"io"
+	"strings"
+)
+
+// CheckDrift reads JSON from r, infers its type as Generate does and compares
+// it with the struct type named opts.Name declared in the Go file, or the Go
+// files of the directory, at path. Keys missing from the struct, struct
+// fields never seen in the input and fields whose declared type can't hold
+// the observed values are written to w, followed by a coverage summary. It
+// returns the number of differences found.
+func CheckDrift(w io.Writer, r io.Reader, path string, opts Options) (int, error) {
+	structs, err := loadStructs(path)
+	if err != nil {
+		return 0, err
+	}
+	st, ok := structs[opts.Name]
+	if !ok {
+		return 0, fmt.Errorf("%s: no struct type named %s", path, opts.Name)
+	}
+	typ, err := inferType(r, opts.Name, &opts.Config)
+	if err != nil {
+		return 0, err
+	}
+	c := &driftChecker{w: w, structs: structs}
+	c.checkStruct(st, typ, opts.Name)
+	fmt.Fprintf(w, "%s: %d of %d fields seen, %d unknown keys, %d type mismatches\n",
+		opts.Name, c.seen, c.fields, c.unknown, c.mismatches)
+	return c.unknown + c.unseen + c.mismatches, nil
+}
+
+// inferType decodes input and returns the merged type of its records, named
+// structName, without the rewrites that only shape the generated code.
+func inferType(input io.Reader, structName string, cfg *Config) (*Type, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	iresult, _, err := readInput(input, cfg)
+	if err != nil {
+		return nil, err
+	}
+	var typ *Type
+	switch iresult := iresult.(type) {
+	case records:
+		typ, err = generateMergedType(structName, iresult, cfg)
+	case []interface{}:
+		typ, err = generateMergedType(structName, iresult, cfg)
+	default:
+		typ, err = generateType(structName, iresult, cfg)
+	}
+	if err != nil {
+		return nil, err
+	}
+	annotateTimeLayouts(typ)
+	return typ, nil
+}
+
+// driftChecker compares inferred types with declared struct types, counting
+// and reporting the differences.
+type driftChecker struct {
+	w       io.Writer
+	structs map[string]*ast.StructType
+
+	fields, seen                int
+	unknown, unseen, mismatches int
+}
+
+// checkStruct compares the fields of the inferred struct type t with those
+// of the declared struct st. path is the Go path of t.
+func (c *driftChecker) checkStruct(st *ast.StructType, t *Type, path string) {
+	fields := map[string]*Type{}
+	for _, field := range t.Children {
+		fields[strings.ToLower(field.Key)] = field
+	}
+	declared := map[string]bool{}
+	for _, field := range structFields(st) {
+		c.fields++
+		key := strings.ToLower(field.key)
+		declared[key] = true
+		fieldPath := path + "." + field.name
+		inferred, ok := fields[key]
+		if !ok {
+			c.unseen++
+			fmt.Fprintf(c.w, "%s: key %q never seen\n", fieldPath, field.key)
+			continue
+		}
+		c.seen++
+		c.checkType(field.typ, inferred, fieldPath)
+	}
+	for _, field := range t.Children {
+		if !declared[strings.ToLower(field.Key)] {
+			c.unknown++
+			fmt.Fprintf(c.w, "%s: key %q not in struct, seen as %s\n", path, field.Key, field.GetType())
+		}
+	}
+}
+
+// checkType reports a mismatch if values of the inferred type t can't be
+// decoded into the declared type expr, and compares nested structs.
+func (c *driftChecker) checkType(expr ast.Expr, t *Type, path string) {
+	if t.nullOnly() || t.emptyArray() {
+		// null and [] decode into anything.
+		return
+	}
+	if t.Observed["null"] > 0 {
+		// nulls decode into anything, so check the non-null values.
+		nonNull := *t
+		if setNonNullType(&nonNull) {
+			t = &nonNull
+		}
+	}
+	elem, repeated := expr, 0
+unwrap:
+	for {
+		switch e := elem.(type) {
+		case *ast.StarExpr:
+			elem = e.X
+		case *ast.ArrayType:
+			if e.Len != nil || isIdent(e.Elt, "byte") {
+				break unwrap
+			}
+			elem, repeated = e.Elt, repeated+1
+		default:
+			break unwrap
+		}
+	}
+	if acceptsAny(elem) {
+		return
+	}
+	if repeated != t.Repeated || !c.holds(elem, t, path) {
+		c.mismatches++
+		observed := conflicts(t)
+		if observed == "" {
+			observed = t.GetType()
+		}
+		fmt.Fprintf(c.w, "%s: declared %s, seen as %s\n", path, types.ExprString(expr), observed)
+	}
+}
+
+// holds reports whether values of the element type of t decode into the
+// declared element type expr, comparing nested structs as it goes.
+func (c *driftChecker) holds(expr ast.Expr, t *Type, path string) bool {
+	if t.Map {
+		_, ok := expr.(*ast.MapType)
+		return ok
+	}
+	switch e := expr.(type) {
+	case *ast.MapType:
+		return t.Type == "struct" || t.Type == "map[string]interface{}"
+	case *ast.StructType:
+		if t.Type != "struct" {
+			return false
+		}
+		c.checkStruct(e, t, path)
+		return true
+	case *ast.ArrayType:
+		// a byte slice, decoded from base64.
+		return stringTypes[t.Type]
+	case *ast.SelectorExpr:
+		switch types.ExprString(e) {
+		case "time.Time", "net.IP":
+			return stringTypes[t.Type]
+		case "json.Number":
+			return numericTypes[t.Type] || t.Type == "json.Number"
+		}
+		// a type from another package.
+		return true
+	case *ast.Ident:
+		if st, ok := c.structs[e.Name]; ok {
+			if t.Type != "struct" {
+				return false
+			}
+			c.checkStruct(st, t, path)
+			return true
+		}
+		switch e.Name {
+		case "string":
+			return stringTypes[t.Type]
+		case "bool":
+			return t.Type == "bool"
+		case "float32", "float64":
+			return numericTypes[t.Type] || t.Type == "json.Number"
+		case "int", "int8", "int16", "int32", "int64", "uint", "uint8", "uint16", "uint32", "uint64":
+			// without integer inference every number is float64.
+			return t.Type == "int64" || t.Type == "int" || (t.Type == "float64" && !t.Config.IntInference)
+		}
+		// a named type declared elsewhere, such as an enum.
+		return true
+	}
+	return true
+}
+
+// acceptsAny reports whether expr is a type any JSON value decodes into.
+func acceptsAny(expr ast.Expr) bool {
+	switch e := expr.(type) {
+	case *ast.InterfaceType:
+		return true
+	case *ast.Ident:
+		return e.Name == "any"
+	case *ast.SelectorExpr:
+		return types.ExprString(e) == "json.RawMessage"
+	}
+	return false
+}
+
+// isIdent reports whether expr is the identifier name.
+func isIdent(expr ast.Expr, name string) bool {
+	ident, ok := expr.(*ast.Ident)
+	return ok && ident.Name == name
+}
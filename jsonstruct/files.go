@@ -0,0 +1,87 @@
+package jsonstruct
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// Files is input read from several files. Generate and CheckDrift decode
+// each file on its own, as they would a single input, and merge the records
+// of every file into one type: the lines of NDJSON files, the elements of
+// top-level arrays and other documents whole. Read returns the contents of
+// the files in turn, separated by newlines.
+type Files struct {
+	names []string
+	files []*os.File
+	r     io.Reader
+}
+
+// OpenFiles opens the named files as a single input.
+func OpenFiles(names ...string) (*Files, error) {
+	f := &Files{names: names}
+	var readers []io.Reader
+	for i, name := range names {
+		file, err := os.Open(name)
+		if err != nil {
+			f.Close()
+			return nil, err
+		}
+		f.files = append(f.files, file)
+		if i > 0 {
+			readers = append(readers, strings.NewReader("\n"))
+		}
+		readers = append(readers, file)
+	}
+	f.r = io.MultiReader(readers...)
+	return f, nil
+}
+
+func (f *Files) Read(p []byte) (int, error) {
+	return f.r.Read(p)
+}
+
+// Close closes the files.
+func (f *Files) Close() error {
+	var err error
+	for _, file := range f.files {
+		if cerr := file.Close(); cerr != nil && err == nil {
+			err = cerr
+		}
+	}
+	return err
+}
+
+// decode decodes each file as readInput does and returns the records of
+// all of them, with the key order of the first. Errors are prefixed with
+// the name of the file.
+func (f *Files) decode(cfg *Config) (records, *keyOrder, error) {
+	var (
+		result records
+		keys   *keyOrder
+	)
+	for i, file := range f.files {
+		if i > 0 && cfg.Sample != nil {
+			if _, err := io.WriteString(cfg.Sample, "\n"); err != nil {
+				return nil, nil, err
+			}
+		}
+		v, fileKeys, err := readInput(file, cfg)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s: %w", f.names[i], err)
+		}
+		if i == 0 {
+			keys = fileKeys
+		}
+		switch v := v.(type) {
+		case records:
+			result = append(result, v...)
+		case []interface{}:
+			result = append(result, v...)
+		default:
+			result = append(result, v)
+		}
+	}
+	return result, keys, nil
+}
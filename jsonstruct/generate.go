@@ -0,0 +1,2498 @@
+// Package jsonstruct generates Go struct definitions from JSON documents.
+//
+// Example:
+//
+//	opts := jsonstruct.Options{Name: "User", Package: "main", Config: jsonstruct.DefaultConfig}
+//	err := jsonstruct.Generate(os.Stdout, strings.NewReader(`{"login": "tmc", "id": 1}`), opts)
+//
+// Output:
+//
+//	package main
+//
+//	type User struct {
+//		ID    int64  `json:"id,omitempty"`
+//		Login string `json:"login,omitempty"`
+//	}
+package jsonstruct
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"io"
+	"math"
+	"net"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+)
+
+type Config struct {
+	// If True, emit "omitempty" tags on output fields.
+	OmitEmpty bool
+	// TagCase controls how json tag names are derived from JSON keys.
+	// One of "original" (the default), "snake", "camel" or "kebab".
+	TagCase string
+	// NameCase controls how field names are derived from JSON keys: "go"
+	// (the default) for Go style names with initialisms, "preserve" for the
+	// key as is, made exported, or "screaming-snake" for upper case words
+	// separated by underscores.
+	NameCase string
+
+	// Log, if non-nil, receives diagnostics such as the number of skipped
+	// NDJSON lines.
+	Log io.Writer
+	// BadLines, if non-nil, receives a copy of each NDJSON line that failed
+	// to parse.
+	BadLines io.Writer
+
+	// If True, a root JSON array is emitted as a named slice type rather than
+	// as its element type.
+	RootAlias bool
+	// If True, a root JSON object is treated as a map whose values are merged
+	// into a single type.
+	TopLevelMap bool
+	// RareDeprecated, if positive, marks fields seen in less than this
+	// fraction of records with a "Deprecated:" comment.
+	RareDeprecated float64
+	// If True, keys that differ only by case, underscores or hyphens are
+	// merged into a single field named after the most frequent spelling.
+	CanonicalizeKeys bool
+	// If True, a NewName constructor is emitted for each generated struct.
+	GenConstructor bool
+	// If True, only the generated declarations are emitted, without the
+	// package clause and imports.
+	BodyOnly bool
+	// If True, only the struct type literal of the root type is emitted,
+	// without a package clause, imports or type name. Nested structs stay
+	// inline, and options that declare named types for fields, such as
+	// Enums, are rejected.
+	Anonymous bool
+	// If True, a MarshalJSON method is emitted for each generated struct that
+	// encodes nil slice fields as [] rather than null. Those fields don't get
+	// omitempty, since it would omit empty slices altogether.
+	GenMarshalers bool
+	// If True, a Reset method that sets every field to its zero value is
+	// emitted for each generated struct, so values can be reused, such as
+	// from a sync.Pool.
+	GenReset bool
+	// If True, string fields holding only IP addresses are emitted as net.IP.
+	DetectIP bool
+	// If True, string fields holding only standard base64, at least
+	// minBase64Len characters long, are emitted as []byte.
+	DetectBytes bool
+	// If True, string fields holding only UUIDs in the 8-4-4-4-12 hex form
+	// are emitted as UUIDType.
+	DetectUUID bool
+	// UUIDType is the Go type of UUID fields, qualified by the import path
+	// of its package. Empty means "github.com/google/uuid.UUID".
+	UUIDType string
+	// MergeReport, if non-nil, receives a summary of the presence, type and
+	// type conflicts of each field.
+	MergeReport io.Writer
+	// SourceMap, if non-nil, receives a JSON object mapping the Go path of
+	// each generated field to the JSON path it was inferred from.
+	SourceMap io.Writer
+	// PolymorphicField, if set, is the discriminator key used to split
+	// arrays of differently shaped objects into variant structs.
+	PolymorphicField string
+	// TimeLayouts lists the time.Parse layouts tried against string values.
+	// Fields whose values all match time.RFC3339 or time.RFC3339Nano are
+	// emitted as time.Time, which only unmarshals RFC 3339. Fields whose
+	// values all match another layout stay strings, commented with the
+	// layout.
+	TimeLayouts []string
+	// TimeUnix, if "seconds" or "millis", annotates numeric fields holding
+	// Unix timestamps in that unit.
+	TimeUnix string
+	// TagQuote is the quoting of struct tags, "backtick" (the default) or
+	// "double". Tags containing a backtick are always double quoted.
+	TagQuote string
+	// ArrayDepth caps the number of nested array dimensions that are
+	// unwrapped. Arrays nested deeper than the cap are emitted with
+	// interface{} elements at the deepest dimension. Zero means no cap.
+	ArrayDepth int
+	// FieldOrder is the order of struct fields: "alphabetical" (the default)
+	// by JSON key, "type-grouped", which puts scalar fields first, then
+	// arrays, then nested structs, each group ordered alphabetically, or
+	// "first-record", which follows the key order of the first object seen
+	// at each level, with any other fields following alphabetically, or
+	// "common-first-alpha", which puts the fields present in the most
+	// objects first, breaking ties alphabetically ignoring case.
+	FieldOrder string
+	// RawFields lists the JSON keys of fields that are emitted as
+	// json.RawMessage, preserving their raw JSON without inference.
+	RawFields []string
+	// If True, fields observed with conflicting types are emitted as
+	// json.RawMessage rather than interface{}.
+	RawOnConflict bool
+	// Lang is the output language: "go" (the default) for Go structs, "zod"
+	// for TypeScript Zod schemas, "jsonschema" for a draft-07 JSON Schema or
+	// "proto" for a proto3 message.
+	Lang string
+	// Format is the input format: "json" (the default), "query" for URL
+	// query strings, "form" for form-encoded bodies or "yaml". Each line of a
+	// query or form input, and each document of a YAML stream, is decoded as
+	// a record.
+	Format string
+	// If True, string values holding JSON objects or arrays are decoded and
+	// their types inferred from the embedded JSON. In Go output, such fields
+	// get a named type whose methods decode and encode it as a JSON string.
+	DetectJSONStrings bool
+	// If True, the column alignment gofmt applies to struct fields is
+	// collapsed to single spaces, so that changing one field doesn't realign
+	// its neighbours.
+	NoTagAlign bool
+	// If True, output is in the canonical form intended for checked-in
+	// golden files, which depends only on the shape of the input: fields and
+	// enum values are ordered alphabetically, tags are sorted by name and
+	// quoted with backticks, fields are aligned in columns as gofmt aligns
+	// them, and example comments, which depend on the order of the records,
+	// are left out. FieldOrder, NoTagAlign, TagQuote and ExampleComments are
+	// ignored.
+	Canonical bool
+	// If True, fields only ever seen as empty objects are emitted as
+	// map[string]interface{} rather than as empty structs.
+	EmptyObjectAsMap bool
+	// JSONNumberFields lists the JSON keys of numeric fields that are
+	// emitted as json.Number, preserving their precision, rather than
+	// float64.
+	JSONNumberFields []string
+	// If True, fields that held the same scalar value in every object are
+	// annotated with a "constant:" comment.
+	DetectConstants bool
+	// If True, fields that held the same scalar value in every object are
+	// removed from their struct and emitted as package constants instead.
+	EmitConstants bool
+	// Nullable, if "generic", emits a generic Nullable[T] wrapper type and
+	// uses it for scalar fields that were null or missing in some objects.
+	// The generated code requires Go 1.18 or later.
+	Nullable string
+	// JSONPath, if set, selects the objects to infer from with a JSONPath
+	// expression such as "$.results[*]", applied to each input document.
+	// Only the steps .name, ['name'], .*, [*] and [n] are supported, not
+	// recursive descent, filters, slices or unions.
+	JSONPath string
+	// ScalarArray decides the type of fields seen both as a scalar and as
+	// an array of that scalar: "any" (the default) emits the conflict type,
+	// and "widen" emits the array type. Decoding a lone scalar into a widened
+	// field needs a custom UnmarshalJSON.
+	ScalarArray string
+	// Example, if non-nil, receives an example JSON document matching the
+	// generated type, filled with the first value observed for each field.
+	Example io.Writer
+	// If True, input that isn't a single JSON document is an error, rather
+	// than being decoded as NDJSON.
+	NoNDJSON bool
+	// NullHeavy, if positive, emits fields that were null in more than this
+	// fraction of records as a pointer to the type of their non-null values,
+	// with a comment noting how sparse they are.
+	NullHeavy float64
+	// If True, numeric fields whose values are all integers in the int64
+	// range are emitted as IntType rather than float64. A field with any
+	// fractional value is always float64, so no value is truncated.
+	IntInference bool
+	// IntType is the type of integer fields, "int64" (the default) or
+	// "int".
+	IntType string
+	// If True, a TypeRegistry variable mapping the name of each generated
+	// struct type to its reflect.Type is emitted.
+	GenRegistry bool
+	// Layout is the order of the declarations in the file: "root-first" (the
+	// default) puts the root type before the types it refers to, and
+	// "leaf-first" puts each type after the types it refers to.
+	Layout string
+	// If True, scalar and struct fields missing from some objects, or seen
+	// as null, are emitted as pointers so that absence is distinguishable
+	// from a zero value.
+	PointersForOptional bool
+	// If True, omitempty is only emitted on fields missing from some objects
+	// or seen as null. Fields present in every object are always encoded.
+	SmartOmitEmpty bool
+	// If True, omitempty is only emitted on fields whose type encoding/json
+	// can omit as empty, such as pointers, slices, maps and strings, and not
+	// on struct, numeric or bool fields.
+	HonestOmitEmpty bool
+	// If True, scalar fields seen both as strings and as numbers are emitted
+	// as a Flexible numeric type whose UnmarshalJSON accepts either a JSON
+	// number or a string holding one.
+	FlexibleTypes bool
+	// If True, fields that were an array of exactly one element in every
+	// object are emitted as the element type. Such input has to be unwrapped
+	// before it can be decoded into the generated type.
+	UnwrapSingletons bool
+	// MaxDepth caps the nesting of generated structs. Objects nested more
+	// than MaxDepth levels below the root are emitted as
+	// map[string]interface{}. Zero means no cap.
+	MaxDepth int
+	// DetectMaps, if positive, emits objects with at least this many keys as
+	// map[string]T when every key looks like an ID rather than a name, such
+	// as "1234" or "user@example.com", and every value has the scalar or
+	// object type T.
+	DetectMaps int
+	// If True, each scalar field is followed by a comment holding the first
+	// value observed for it, such as // e.g. "octocat".
+	ExampleComments bool
+	// StructComment, if set, is the doc comment of the top-level type. Each
+	// line of it is emitted as a line comment.
+	StructComment string
+	// FieldTypes maps JSON keys to the Go type emitted for fields with that
+	// key, overriding inference. A type from a package other than encoding/json,
+	// net and time is qualified by its import path, as in
+	// "github.com/shopspring/decimal.Decimal".
+	FieldTypes map[string]string
+	// If True, it is an error for any field to be missing from some of the
+	// objects it could have appeared in, unless its JSON key is listed in
+	// Optional.
+	RequireAllPresent bool
+	// Optional lists the JSON keys of fields allowed to be missing when
+	// RequireAllPresent is set.
+	Optional []string
+	// If True, numbers are decoded without loss of precision and numeric
+	// fields are emitted as json.Number.
+	UseJSONNumber bool
+	// EmbedSample, if set, is the path of a sample file embedded with a
+	// //go:embed directive in a byte slice variable named after the struct.
+	// The path is relative to the generated file's directory.
+	EmbedSample string
+	// Sample, if non-nil, receives a copy of the input, to be saved as the
+	// EmbedSample file.
+	Sample io.Writer
+	// Tags lists the struct tags emitted for each field: "json", "yaml" and
+	// "bson". Empty means just json, which is omitted when it would match the
+	// field name. yaml tags use the JSON key lowercased, and bson tags use it
+	// verbatim, so that a key of "_id" maps to MongoDB's document ID.
+	Tags []string
+	// KnownTypes maps the names of existing struct types to their fields.
+	// Nested structs with the same keys, whose values decode into the types
+	// of those fields, are emitted as references to those types rather than
+	// as new struct types.
+	KnownTypes map[string][]KnownField
+	// Histogram, if non-nil, receives an ASCII histogram of the values of
+	// each numeric field.
+	Histogram io.Writer
+	// NumberSample is the number of values of each numeric field kept for
+	// histograms and their percentiles, 10000 if zero. Past it, a uniform
+	// sample of the values is kept instead: a larger sample gives more
+	// accurate percentiles, a smaller one uses less memory on large inputs.
+	NumberSample int
+	// If True, each nested object is emitted as a named struct type, named
+	// after the path to it, rather than as an inline struct.
+	NamedNested bool
+	// Initialisms lists words, in addition to the common initialisms such as
+	// API, HTTP and ID, that are upper cased when they make up an underscore
+	// separated part of a field name.
+	Initialisms []string
+	// Enums, if positive, emits string fields, and integer fields holding
+	// values from -100 to 100, with fewer than this many distinct values
+	// across all records as a named type, with a constant for each value.
+	Enums int
+	// Parallel, if greater than one, is the number of goroutines that infer
+	// and merge the types of the records of large inputs. The generated code
+	// is the same as without it.
+	Parallel int
+	// SampleLimit, if positive, is the number of records types are inferred
+	// from: the first NDJSON objects or root array elements. JSON input is
+	// read no further than the last of them.
+	SampleLimit int
+	// Records, if non-nil, is set to the number of records types were
+	// inferred from, after decompressing, decoding and selecting them with
+	// JSONPath.
+	Records *int
+}
+
+// hasTag reports whether fields get the struct tag named tag.
+func (c *Config) hasTag(tag string) bool {
+	if len(c.Tags) == 0 {
+		return tag == "json"
+	}
+	return containsKey(c.Tags, tag)
+}
+
+// intType returns the type emitted for integer fields.
+func (c *Config) intType() string {
+	if c.IntType == "" {
+		return "int64"
+	}
+	return c.IntType
+}
+
+// numberSample returns the number of values of each numeric field kept
+// for histograms.
+func (c *Config) numberSample() int {
+	if c.NumberSample == 0 {
+		return maxNumbers
+	}
+	return c.NumberSample
+}
+
+// recordExamples reports whether the first value observed for each field is
+// recorded in its Example.
+func (c *Config) recordExamples() bool {
+	return c.Example != nil || c.ExampleComments
+}
+
+// conflictType returns the type emitted for values observed with
+// conflicting types.
+func (c *Config) conflictType() string {
+	if c.RawOnConflict {
+		return "json.RawMessage"
+	}
+	return "interface{}"
+}
+
+// isRawField reports whether fields with the JSON key key are emitted as
+// json.RawMessage without inference.
+func (c *Config) isRawField(key string) bool {
+	return containsKey(c.RawFields, key)
+}
+
+// fieldType returns the Go type pinned for fields with the JSON key key, and
+// the import path it needs, if any.
+func (c *Config) fieldType(key string) (typ, importPath string, ok bool) {
+	typ, ok = c.FieldTypes[key]
+	if !ok {
+		return "", "", false
+	}
+	typ, importPath = qualifiedType(typ)
+	return typ, importPath, true
+}
+
+// uuidType returns the Go type of UUID fields and its import path.
+func (c *Config) uuidType() (typ, importPath string) {
+	if c.UUIDType == "" {
+		return qualifiedType("github.com/google/uuid.UUID")
+	}
+	return qualifiedType(c.UUIDType)
+}
+
+// qualifiedType splits a Go type qualified by the import path of its
+// package into the type as written in code and the import path, which is
+// empty for predeclared types.
+//
+// Example:
+// 	qualifiedType("*github.com/shopspring/decimal.Decimal")
+// Output: *decimal.Decimal github.com/shopspring/decimal
+func qualifiedType(typ string) (string, string) {
+	prefix := typ[:len(typ)-len(strings.TrimLeft(typ, "*[]"))]
+	qualified := typ[len(prefix):]
+	dot := strings.LastIndex(qualified, ".")
+	if dot < 0 {
+		return typ, ""
+	}
+	importPath := qualified[:dot]
+	name := packageName(importPath) + qualified[dot:]
+	if path, ok := importPaths[importPath]; ok {
+		importPath = path
+	}
+	return prefix + name, importPath
+}
+
+var majorVersion = regexp.MustCompile(`^v[0-9]+$`)
+
+// packageName returns the conventional name of the package with the import
+// path importPath, its last element without any major version suffix.
+//
+// Example:
+// 	packageName("github.com/gofrs/uuid/v5")
+// Output: uuid
+func packageName(importPath string) string {
+	elems := strings.Split(importPath, "/")
+	name := elems[len(elems)-1]
+	if majorVersion.MatchString(name) && len(elems) > 1 {
+		name = elems[len(elems)-2]
+	}
+	if i := strings.Index(name, ".v"); i > 0 && majorVersion.MatchString(name[i+1:]) {
+		// gopkg.in/yaml.v3
+		name = name[:i]
+	}
+	return name
+}
+
+// isJSONNumberField reports whether numeric fields with the JSON key key are
+// emitted as json.Number.
+func (c *Config) isJSONNumberField(key string) bool {
+	return containsKey(c.JSONNumberFields, key)
+}
+
+// containsKey reports whether keys contains key.
+func containsKey(keys []string, key string) bool {
+	for _, k := range keys {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
+
+var DefaultConfig = Config{
+	OmitEmpty:    true,
+	ArrayDepth:   3,
+	IntInference: true,
+}
+
+// Options configures Generate.
+type Options struct {
+	// Name is the name of the generated type.
+	Name string
+	// Package is the name of the package clause of the generated file.
+	Package string
+	Config
+}
+
+// Generate reads JSON from r and writes Go source declaring a type named
+// opts.Name that it decodes into to w.
+func Generate(w io.Writer, r io.Reader, opts Options) error {
+	output, err := generate(r, opts.Name, opts.Package, &opts.Config)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(output)
+	return err
+}
+
+// Generator generates Go types from JSON with a validated set of options.
+type Generator struct {
+	opts Options
+}
+
+// NewGenerator returns a Generator using opts, or an error if any of its
+// options has an unknown value.
+func NewGenerator(opts Options) (*Generator, error) {
+	if err := opts.Config.Validate(); err != nil {
+		return nil, err
+	}
+	return &Generator{opts: opts}, nil
+}
+
+// Generate reads JSON from r and writes Go source declaring the type it
+// decodes into to w, as the Generate function does.
+func (g *Generator) Generate(w io.Writer, r io.Reader) error {
+	return Generate(w, r, g.opts)
+}
+
+// Validate reports an error if any option of c has an unknown value, such as
+// a misspelled FieldOrder, or if c combines options that can't be used
+// together. Generate validates its options before reading any input.
+func (c *Config) Validate() error {
+	switch c.TagCase {
+	case "", "original", "snake", "camel", "kebab":
+	default:
+		return fmt.Errorf("unknown tag case: %q", c.TagCase)
+	}
+	switch c.NameCase {
+	case "", "go", "preserve", "screaming-snake":
+	default:
+		return fmt.Errorf("unknown name case: %q", c.NameCase)
+	}
+	switch c.TagQuote {
+	case "", "backtick", "double":
+	default:
+		return fmt.Errorf("unknown tag quote style: %q", c.TagQuote)
+	}
+	switch c.Lang {
+	case "", "go", "zod", "jsonschema", "proto":
+	default:
+		return fmt.Errorf("unknown output language: %q", c.Lang)
+	}
+	switch c.Format {
+	case "", "json", "query", "form", "yaml":
+	default:
+		return fmt.Errorf("unknown input format: %q", c.Format)
+	}
+	switch c.FieldOrder {
+	case "", "alphabetical", "type-grouped", "first-record", "common-first-alpha":
+	default:
+		return fmt.Errorf("unknown field order: %q", c.FieldOrder)
+	}
+	switch c.ScalarArray {
+	case "", "any", "widen":
+	default:
+		return fmt.Errorf("unknown scalar/array strategy: %q", c.ScalarArray)
+	}
+	for _, tag := range c.Tags {
+		if tag != "json" && tag != "yaml" && tag != "bson" {
+			return fmt.Errorf("unknown struct tag: %q", tag)
+		}
+	}
+	if c.UUIDType != "" && !strings.Contains(strings.TrimLeft(c.UUIDType, "*"), ".") {
+		return fmt.Errorf("invalid UUID type %q: want a type qualified by its import path", c.UUIDType)
+	}
+	if c.EmbedSample != "" {
+		if err := checkEmbedPath(c.EmbedSample); err != nil {
+			return err
+		}
+	}
+	switch c.Layout {
+	case "", "root-first", "leaf-first":
+	default:
+		return fmt.Errorf("unknown layout: %q", c.Layout)
+	}
+	switch c.IntType {
+	case "", "int64", "int":
+	default:
+		return fmt.Errorf("unknown integer type: %q", c.IntType)
+	}
+	switch c.Nullable {
+	case "", "generic":
+	default:
+		return fmt.Errorf("unknown nullable style: %q", c.Nullable)
+	}
+	switch c.TimeUnix {
+	case "", "seconds", "millis":
+	default:
+		return fmt.Errorf("unknown unix time unit: %q", c.TimeUnix)
+	}
+	if c.NumberSample < 0 {
+		return fmt.Errorf("invalid number sample size: %d", c.NumberSample)
+	}
+	if c.JSONPath != "" {
+		if _, err := parseJSONPath(c.JSONPath); err != nil {
+			return err
+		}
+	}
+	if opt := c.namedTypeOption(); c.Anonymous && opt != "" && (c.Lang == "" || c.Lang == "go") {
+		// the struct literal would refer to types it doesn't declare.
+		return fmt.Errorf("anonymous output can't be combined with %s, which declares named types", opt)
+	}
+	return nil
+}
+
+// namedTypeOption returns the name of an option of c that makes fields refer
+// to named types declared alongside the struct, or "" if none is set.
+// NamedNested isn't one of them: without type names nested structs stay
+// inline.
+func (c *Config) namedTypeOption() string {
+	switch {
+	case c.Enums > 0:
+		return "Enums"
+	case c.FlexibleTypes:
+		return "FlexibleTypes"
+	case c.Nullable == "generic":
+		return "Nullable"
+	case c.DetectJSONStrings:
+		return "DetectJSONStrings"
+	case c.PolymorphicField != "":
+		return "PolymorphicField"
+	}
+	return ""
+}
+
+// countRecords returns the number of records in the decoded input v: the
+// number of NDJSON records or root array elements, or 1 for a single value.
+func countRecords(v interface{}) int {
+	switch v := v.(type) {
+	case records:
+		return len(v)
+	case []interface{}:
+		return len(v)
+	}
+	return 1
+}
+
+// Given a JSON string representation of an object and a name structName,
+// attemp to generate a struct definition
+func generate(input io.Reader, structName, pkgName string, cfg *Config) ([]byte, error) {
+	if cfg == nil {
+		cfg = &DefaultConfig
+	}
+	if cfg.Canonical {
+		c := *cfg
+		c.FieldOrder = "alphabetical"
+		c.NoTagAlign = false
+		c.TagQuote = "backtick"
+		c.ExampleComments = false
+		cfg = &c
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	iresult, keys, err := readInput(input, cfg)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Records != nil {
+		*cfg.Records = countRecords(iresult)
+	}
+
+	var typ *Type
+	rootArray := false
+	topLevelMap := false
+	switch iresult := iresult.(type) {
+	case map[string]interface{}:
+		if !cfg.TopLevelMap {
+			typ, err = generateType(structName, iresult, cfg)
+			break
+		}
+		keys := make([]string, 0, len(iresult))
+		for key := range iresult {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		values := make([]interface{}, 0, len(keys))
+		for _, key := range keys {
+			values = append(values, iresult[key])
+		}
+		typ, err = generateMergedType(structName, values, cfg)
+		topLevelMap = true
+	case records:
+		typ, err = generateMergedType(structName, iresult, cfg)
+	case []interface{}:
+		typ, err = generateMergedType(structName, iresult, cfg)
+		rootArray = true
+	default:
+		// a bare scalar, such as "hello" or 42, is emitted as its type.
+		typ, err = generateType(structName, iresult, cfg)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var pinned []string
+	if len(cfg.FieldTypes) > 0 {
+		pinned = pinnedKeys(typ, cfg)
+	}
+	if cfg.CanonicalizeKeys {
+		canonicalizeKeys(typ, structName, cfg.Log)
+	}
+	if cfg.DetectMaps > 0 {
+		if err := detectMaps(typ, cfg.DetectMaps); err != nil {
+			return nil, err
+		}
+	}
+	if cfg.EmptyObjectAsMap {
+		emptyObjectsAsMaps(typ)
+	}
+	if cfg.MaxDepth > 0 {
+		capDepth(typ, cfg.MaxDepth)
+	}
+	if topLevelMap && keys != nil && len(keys.keys) > 0 {
+		// the root object's values are the records.
+		keys = keys.field(keys.keys[0])
+	}
+	nameBlankFields(typ)
+	sortFields(typ, cfg.FieldOrder, keys)
+	if len(cfg.KnownTypes) > 0 {
+		matchKnownTypes(typ, structName, cfg.KnownTypes, cfg.Log)
+	}
+	annotateTimeLayouts(typ)
+	if cfg.UnwrapSingletons {
+		unwrapSingletons(typ)
+	}
+	if cfg.RequireAllPresent {
+		if missing := missingFields(typ, structName, cfg.Optional); len(missing) > 0 {
+			return nil, fmt.Errorf("fields missing from some records: %s", strings.Join(missing, ", "))
+		}
+	}
+	if cfg.RareDeprecated > 0 {
+		markRareDeprecated(typ, cfg.RareDeprecated)
+	}
+	if cfg.NullHeavy > 0 {
+		markNullHeavy(typ, cfg.NullHeavy)
+	}
+	if cfg.PointersForOptional {
+		pointersForOptional(typ)
+	}
+	if cfg.SmartOmitEmpty {
+		keepRequiredEmpty(typ)
+	}
+	if cfg.MergeReport != nil {
+		writeMergeReport(cfg.MergeReport, typ)
+	}
+	if cfg.Histogram != nil {
+		writeHistograms(cfg.Histogram, typ)
+	}
+	if cfg.SourceMap != nil {
+		rootPath := "$"
+		switch {
+		case rootArray:
+			rootPath = "$[*]"
+		case topLevelMap:
+			rootPath = "$.*"
+		}
+		if err := writeSourceMap(cfg.SourceMap, typ, rootPath); err != nil {
+			return nil, err
+		}
+	}
+	if cfg.Example != nil {
+		example := exampleJSON(typ)
+		switch {
+		case rootArray:
+			example = "[" + example + "]"
+		case topLevelMap:
+			example = `{"key":` + example + "}"
+		}
+		if err := writeExample(cfg.Example, example); err != nil {
+			return nil, err
+		}
+	}
+
+	if cfg.Lang == "zod" || cfg.Lang == "jsonschema" {
+		container := ""
+		switch {
+		case rootArray && cfg.RootAlias:
+			container = "[]"
+		case topLevelMap:
+			container = "map[string]"
+		}
+		if cfg.Lang == "jsonschema" {
+			return renderJSONSchema(typ, structName, container)
+		}
+		return renderZod(typ, structName, container), nil
+	}
+	if cfg.Lang == "proto" {
+		return renderProto(typ, structName, pkgName), nil
+	}
+
+	var extraDecls, extraImports []string
+
+	// used holds the names declared so far, so that generated names don't
+	// collide.
+	used := map[string]bool{structName: true}
+	switch {
+	case rootArray && cfg.RootAlias:
+		used[structName+"Element"] = true
+	case topLevelMap:
+		used[mapValueName(structName)] = true
+	}
+	if cfg.FlexibleTypes {
+		for _, base := range []string{"int64", "int", "float64"} {
+			used[flexibleName(base)] = true
+		}
+	}
+	if cfg.Nullable == "generic" {
+		used["Nullable"] = true
+	}
+	if cfg.EmbedSample != "" {
+		used[embedVarName(structName)] = true
+	}
+	if cfg.GenRegistry {
+		used["TypeRegistry"] = true
+	}
+
+	var constDecls []string
+	if cfg.DetectConstants || cfg.EmitConstants {
+		constDecls = detectConstants(typ, structName, cfg.EmitConstants, used)
+	}
+
+	types := []*Type{typ}
+	if cfg.FlexibleTypes {
+		for _, base := range flexibleTypes(typ) {
+			extraDecls = append(extraDecls, flexibleDecl(base))
+			extraImports = append(extraImports, "encoding/json")
+		}
+	}
+	if cfg.Nullable == "generic" && wrapNullable(typ) {
+		extraDecls = append(extraDecls, nullableDecl)
+		extraImports = append(extraImports, "encoding/json")
+	}
+	if cfg.Enums > 0 {
+		// after wrapNullable, so that nullable enum fields wrap the enum type.
+		extraDecls = append(extraDecls, enumDecls(typ, structName, cfg, used)...)
+	}
+	if cfg.DetectUUID {
+		// after the nullable passes, which take the type of nullable fields
+		// from the types they were observed as.
+		uuidType, path := cfg.uuidType()
+		if setUUIDTypes(typ, uuidType) && path != "" {
+			extraImports = append(extraImports, path)
+		}
+	}
+	if cfg.DetectJSONStrings {
+		decls, jsonTypes := jsonStringDecls(typ, structName, used)
+		extraDecls = append(extraDecls, decls...)
+		if len(decls) > 0 {
+			types = append(types, jsonTypes...)
+			extraImports = append(extraImports, "encoding/json")
+		}
+	}
+	if cfg.PolymorphicField != "" {
+		decls, variants := polymorphicDecls(typ, structName, cfg.PolymorphicField, used)
+		extraDecls = append(extraDecls, decls...)
+		if len(decls) > 0 {
+			types = append(types, variants...)
+			extraImports = append(extraImports, "encoding/json", "fmt")
+		}
+	}
+
+	var nested []*Type
+	if cfg.NamedNested && !cfg.Anonymous {
+		nested = extractNested(typ, structName, used)
+		types = append(types, nested...)
+	}
+
+	if cfg.GenMarshalers {
+		// the marshaler encodes nil slices as [], which omitempty would drop.
+		for _, t := range append([]*Type{typ}, nested...) {
+			for _, field := range t.Children {
+				if field.Repeated > 0 {
+					field.KeepEmpty = true
+				}
+			}
+		}
+	}
+
+	decls := []string{"type " + typ.String()}
+	named := []*Type{typ}
+	switch {
+	case rootArray && cfg.RootAlias:
+		decls, named = containerDecls(structName, "[]", structName+"Element", typ)
+	case topLevelMap:
+		decls, named = containerDecls(structName, "map[string]", mapValueName(structName), typ)
+	}
+	if cfg.StructComment != "" {
+		root := &Type{Comment: cfg.StructComment}
+		decls[0] = root.GetComment() + decls[0]
+	}
+	for _, t := range nested {
+		decls = append(decls, "type "+t.String())
+	}
+	named = append(named, nested...)
+	if cfg.GenConstructor {
+		structs := map[string]*Type{}
+		for _, t := range named {
+			if t.Type == "struct" && t.Repeated == 0 {
+				structs[t.Name] = t
+			}
+		}
+		for _, t := range named {
+			if structs[t.Name] == t {
+				decls = append(decls, constructorDecl(t, structs))
+			}
+		}
+	}
+	if cfg.GenReset {
+		for _, t := range named {
+			if t.Type == "struct" && t.Repeated == 0 {
+				decls = append(decls, resetDecl(t))
+			}
+		}
+	}
+	if cfg.GenMarshalers {
+		for _, t := range named {
+			if decl := marshalerDecl(t); t.Type == "struct" && t.Repeated == 0 && decl != "" {
+				decls = append(decls, decl)
+				extraImports = append(extraImports, "encoding/json")
+			}
+		}
+	}
+	if cfg.Layout == "leaf-first" {
+		if len(decls) > 1 && ((rootArray && cfg.RootAlias) || topLevelMap) {
+			// move the container type after its element type.
+			decls = append(decls[1:], decls[0])
+		}
+		decls = append(extraDecls, decls...)
+	} else {
+		decls = append(decls, extraDecls...)
+	}
+	decls = append(decls, constDecls...)
+	if cfg.EmbedSample != "" {
+		decls = append(decls, embedDecl(structName, cfg.EmbedSample))
+		extraImports = append(extraImports, "embed")
+	}
+	if cfg.GenRegistry {
+		// nested types are both named and among the types.
+		names := []string{structName}
+		seen := map[string]bool{structName: true}
+		for _, t := range append(named, types[1:]...) {
+			if !seen[t.Name] {
+				seen[t.Name] = true
+				names = append(names, t.Name)
+			}
+		}
+		decls = append(decls, registryDecl(names))
+		extraImports = append(extraImports, "reflect")
+	}
+	if cfg.Anonymous {
+		literal := typ.GetTypeLiteral()
+		switch {
+		case rootArray && cfg.RootAlias:
+			literal = "[]" + literal
+		case topLevelMap:
+			literal = "map[string]" + literal
+		}
+		decls = []string{"type _ " + literal}
+	}
+	src := fmt.Sprintf("package %s\n", pkgName)
+	for _, key := range pinned {
+		if _, path, _ := cfg.fieldType(key); path != "" {
+			extraImports = append(extraImports, path)
+		}
+	}
+	if imports := collectImports(types, extraImports...); len(imports) > 0 {
+		src += "\n" + importBlock(imports)
+	}
+	for _, decl := range decls {
+		src += "\n" + decl + "\n"
+	}
+	formatted, err := format.Source([]byte(src))
+	if err != nil {
+		return nil, fmt.Errorf("error formatting: %s, was formatting\n%s", err, src)
+	}
+	if cfg.NoTagAlign {
+		formatted = collapseAlignment(formatted)
+		if _, err := parser.ParseFile(token.NewFileSet(), "", formatted, 0); err != nil {
+			return nil, fmt.Errorf("error collapsing alignment: %s", err)
+		}
+	}
+	if cfg.BodyOnly || cfg.Anonymous {
+		formatted = stripPreamble(formatted)
+	}
+	if cfg.Anonymous {
+		formatted = bytes.TrimPrefix(formatted, []byte("type _ "))
+	}
+	return formatted, nil
+}
+
+// collapseAlignment replaces runs of spaces in the Go source src with a
+// single space, leaving indentation, comments and string literals alone.
+func collapseAlignment(src []byte) []byte {
+	lines := strings.Split(string(src), "\n")
+	for i, line := range lines {
+		code := strings.TrimLeft(line, "\t")
+		if strings.HasPrefix(code, "//") {
+			continue
+		}
+		var b strings.Builder
+		b.WriteString(line[:len(line)-len(code)])
+		var quote rune
+		for j, r := range code {
+			switch {
+			case quote != 0:
+				if r == quote && (quote == '`' || !escaped(code[:j])) {
+					quote = 0
+				}
+			case r == '"' || r == '`':
+				quote = r
+			case r == ' ' && strings.HasSuffix(b.String(), " "):
+				continue
+			}
+			b.WriteRune(r)
+		}
+		lines[i] = b.String()
+	}
+	return []byte(strings.Join(lines, "\n"))
+}
+
+// escaped reports whether a character following s is escaped by an odd
+// number of trailing backslashes.
+func escaped(s string) bool {
+	n := len(s) - len(strings.TrimRight(s, "\\"))
+	return n%2 == 1
+}
+
+// stripPreamble returns the declarations in the Go source file src without
+// its package clause and imports.
+func stripPreamble(src []byte) []byte {
+	f, err := parser.ParseFile(token.NewFileSet(), "", src, 0)
+	if err != nil {
+		return src
+	}
+	end := f.Name.End()
+	for _, decl := range f.Decls {
+		if d, ok := decl.(*ast.GenDecl); ok && d.Tok == token.IMPORT {
+			end = d.End()
+		}
+	}
+	return bytes.TrimLeft(src[end-1:], "\n")
+}
+
+// importPaths maps the package qualifiers used in generated types to their
+// import paths.
+var importPaths = map[string]string{
+	"json": "encoding/json",
+	"net":  "net",
+	"time": "time",
+}
+
+var qualifiedIdent = regexp.MustCompile(`([A-Za-z_][A-Za-z0-9_]*)\.[A-Z]`)
+
+// collectImports returns the sorted import paths of the packages referenced
+// by types and their children, along with extra.
+func collectImports(types []*Type, extra ...string) []string {
+	seen := map[string]bool{}
+	for _, path := range extra {
+		seen[path] = true
+	}
+	var walk func(t *Type)
+	walk = func(t *Type) {
+		for _, m := range qualifiedIdent.FindAllStringSubmatch(t.Type, -1) {
+			if path, ok := importPaths[m[1]]; ok {
+				seen[path] = true
+			}
+		}
+		for _, child := range t.Children {
+			walk(child)
+		}
+	}
+	for _, typ := range types {
+		walk(typ)
+	}
+	imports := make([]string, 0, len(seen))
+	for path := range seen {
+		imports = append(imports, path)
+	}
+	sort.Strings(imports)
+	return imports
+}
+
+// pinnedKeys returns the sorted keys of cfg.FieldTypes that are the key of a
+// field of typ or of its nested structs. The others are reported to cfg.Log.
+func pinnedKeys(typ *Type, cfg *Config) []string {
+	seen := map[string]bool{}
+	var walk func(t *Type)
+	walk = func(t *Type) {
+		for _, field := range t.Children {
+			for key := range field.Keys {
+				seen[key] = true
+			}
+			walk(field)
+		}
+		for _, variant := range t.Variants {
+			walk(variant)
+		}
+	}
+	walk(typ)
+	var keys []string
+	for key := range cfg.FieldTypes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	found := keys[:0]
+	for _, key := range keys {
+		if seen[key] {
+			found = append(found, key)
+		} else if cfg.Log != nil {
+			fmt.Fprintf(cfg.Log, "warning: no field with key %q to pin to %s\n", key, cfg.FieldTypes[key])
+		}
+	}
+	return found
+}
+
+// importBlock returns an import declaration for the sorted import paths
+// imports, with the standard library packages grouped before the others.
+func importBlock(imports []string) string {
+	var std, other strings.Builder
+	for _, path := range imports {
+		b := &std
+		if strings.Contains(strings.SplitN(path, "/", 2)[0], ".") {
+			b = &other
+		}
+		if path == "embed" {
+			// embed is only imported for its //go:embed directive.
+			b.WriteString("_ ")
+		}
+		fmt.Fprintf(b, "%q\n", path)
+	}
+	if std.Len() > 0 && other.Len() > 0 {
+		std.WriteString("\n")
+	}
+	return "import (\n" + std.String() + other.String() + ")\n"
+}
+
+// generateMergedType generates a type for each of values and merges them into
+// a single type.
+func generateMergedType(name string, values []interface{}, cfg *Config) (*Type, error) {
+	if len(values) == 0 {
+		return nil, fmt.Errorf("empty array")
+	}
+	if cfg.Parallel > 1 && len(values) >= minParallelValues {
+		return generateMergedTypeParallel(name, values, cfg)
+	}
+	typ, err := generateType(name, values[0], cfg)
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range values[1:] {
+		t2, err := generateType(name, v, cfg)
+		if err != nil {
+			return nil, err
+		}
+		if err := typ.Merge(t2); err != nil {
+			return nil, fmt.Errorf("issue merging: %w", err)
+		}
+	}
+	return typ, nil
+}
+
+// minParallelValues is the number of values below which merging them in
+// parallel isn't worth starting goroutines.
+const minParallelValues = 1000
+
+// generateMergedTypeParallel is generateMergedType with the values split
+// into cfg.Parallel consecutive chunks, each merged by its own goroutine. The
+// chunk types are then merged in order, so the result doesn't depend on
+// scheduling.
+func generateMergedTypeParallel(name string, values []interface{}, cfg *Config) (*Type, error) {
+	serial := *cfg
+	serial.Parallel = 0
+	n := cfg.Parallel
+	chunks := make([]*Type, n)
+	errs := make([]error, n)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		chunk := values[i*len(values)/n : (i+1)*len(values)/n]
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			chunks[i], errs[i] = generateMergedType(name, chunk, &serial)
+		}(i)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	typ := chunks[0]
+	for _, t2 := range chunks[1:] {
+		if err := typ.Merge(t2); err != nil {
+			return nil, fmt.Errorf("issue merging: %w", err)
+		}
+	}
+	setConfig(typ, cfg)
+	return typ, nil
+}
+
+// setConfig sets the Config of typ, its fields and its variants to cfg.
+func setConfig(typ *Type, cfg *Config) {
+	typ.Config = cfg
+	for _, field := range typ.Children {
+		setConfig(field, cfg)
+	}
+	for _, variant := range typ.Variants {
+		setConfig(variant, cfg)
+	}
+}
+
+// containerDecls returns the declarations for a named container type, such as
+// "[]" or "map[string]", holding elements of type elem. Struct elements are
+// declared as their own type named elemName, which is returned along with the
+// declarations.
+func containerDecls(name, container, elemName string, elem *Type) ([]string, []*Type) {
+	if elem.Type != "struct" {
+		return []string{fmt.Sprintf("type %s %s%s", name, container, elem.GetType())}, nil
+	}
+	elem.Name = elemName
+	return []string{
+		fmt.Sprintf("type %s %s%s", name, container, elem.Name),
+		"type " + elem.String(),
+	}, []*Type{elem}
+}
+
+// checkEmbedPath returns an error if path can't be used in a //go:embed
+// directive.
+func checkEmbedPath(path string) error {
+	if path == "" || strings.HasPrefix(path, "/") || strings.Contains(path, "\\") {
+		return fmt.Errorf("invalid embed path %q: must be a relative, slash-separated path", path)
+	}
+	for _, elem := range strings.Split(path, "/") {
+		if elem == "" || elem == "." || elem == ".." || strings.ContainsAny(elem, "*?[\"`") {
+			return fmt.Errorf("invalid embed path %q", path)
+		}
+	}
+	return nil
+}
+
+// embedDecl returns a byte slice variable declaration, named after the
+// struct name, embedding the file at path.
+func embedDecl(name, path string) string {
+	pattern := path
+	if strings.ContainsAny(path, " \t") {
+		pattern = strconv.Quote(path)
+	}
+	varName := embedVarName(name)
+	return fmt.Sprintf("// %s holds the sample JSON %s was generated from.\n//\n//go:embed %s\nvar %s []byte",
+		varName, name, pattern, varName)
+}
+
+// embedVarName returns the name of the variable embedding the sample the
+// struct name was generated from.
+func embedVarName(name string) string {
+	return strings.ToLower(name[:1]) + name[1:] + "Sample"
+}
+
+// extractNested replaces the type of each nested struct field of typ with a
+// named struct type, named prefix followed by the field name, singular for
+// arrays, and does the same for the fields of those types. Each name thus
+// spells out the whole path, so that generic keys such as x or data are named
+// after what holds them, as in PointX. Names already used get a numeric
+// suffix, and the names chosen are added to used. It returns the named types
+// in the order they were found.
+func extractNested(typ *Type, prefix string, used map[string]bool) []*Type {
+	var result []*Type
+	for _, field := range typ.Children {
+		if field.Type != "struct" {
+			continue
+		}
+		name := prefix + field.Name
+		if field.Repeated > 0 || field.Map {
+			name = prefix + singularize(field.Name)
+		}
+		name = uniqueName(used, name)
+		t := &Type{Name: name, Type: "struct", Children: field.Children, Config: field.Config}
+		field.Type, field.Children = name, nil
+		result = append(result, t)
+		result = append(result, extractNested(t, name, used)...)
+	}
+	return result
+}
+
+// nameBlankFields names the fields of typ, and of its nested structs and
+// variants, whose keys carry no letters or digits, such as "$", Field1,
+// Field2 and so on, skipping the names of other fields.
+func nameBlankFields(typ *Type) {
+	used := map[string]bool{}
+	for _, field := range typ.Children {
+		used[field.Name] = true
+	}
+	n := 0
+	for _, field := range typ.Children {
+		nameBlankFields(field)
+		if strings.Trim(field.Name, "_") != "" {
+			continue
+		}
+		n++
+		for used["Field"+strconv.Itoa(n)] {
+			n++
+		}
+		field.Name = "Field" + strconv.Itoa(n)
+		used[field.Name] = true
+	}
+	for _, variant := range typ.Variants {
+		nameBlankFields(variant)
+	}
+}
+
+// uniqueName returns name, or name followed by the smallest number from 2 up
+// that makes it unique, if name is already in used, and adds the result to
+// used.
+func uniqueName(used map[string]bool, name string) string {
+	if used[name] {
+		i := 2
+		for used[name+strconv.Itoa(i)] {
+			i++
+		}
+		name += strconv.Itoa(i)
+	}
+	used[name] = true
+	return name
+}
+
+// registryDecl returns a TypeRegistry variable declaration mapping each of
+// the type names to its reflect.Type.
+func registryDecl(names []string) string {
+	var b strings.Builder
+	b.WriteString("// TypeRegistry maps the name of each generated type to its reflect.Type.\n")
+	b.WriteString("var TypeRegistry = map[string]reflect.Type{\n")
+	for _, name := range names {
+		fmt.Fprintf(&b, "%q: reflect.TypeOf((*%s)(nil)).Elem(),\n", name, name)
+	}
+	b.WriteString("}")
+	return b.String()
+}
+
+// marshalerDecl returns a MarshalJSON method for the named struct type typ
+// that encodes its nil slice fields as empty JSON arrays rather than null, or
+// an empty string if typ has no slice fields.
+func marshalerDecl(typ *Type) string {
+	var fields strings.Builder
+	for _, field := range typ.Children {
+		if field.Repeated > 0 {
+			fmt.Fprintf(&fields, "if a.%s == nil {\na.%s = %s{}\n}\n", field.Name, field.Name, field.GetTypeLiteral())
+		}
+	}
+	if fields.Len() == 0 {
+		return ""
+	}
+	return fmt.Sprintf(`// MarshalJSON encodes v with nil slice fields encoded as [] rather than null.
+func (v %s) MarshalJSON() ([]byte, error) {
+	type alias %s
+	a := alias(v)
+	%sreturn json.Marshal(a)
+}`, typ.Name, typ.Name, fields.String())
+}
+
+// constructorDecl returns a constructor function for the named struct type
+// typ that initializes its slice fields, and those of its nested structs, to
+// empty, rather than nil, slices. Fields of the struct types in named, keyed
+// by name, are initialized with their own constructors.
+func constructorDecl(typ *Type, named map[string]*Type) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "// New%s returns a new %s with its slice fields initialized to empty slices.\n", typ.Name, typ.Name)
+	fmt.Fprintf(&b, "func New%s() *%s {\nreturn &%s{\n", typ.Name, typ.Name, typ.Name)
+	b.WriteString(sliceInits(typ, named))
+	b.WriteString("}\n}")
+	return b.String()
+}
+
+// sliceInits returns the keyed elements of a composite literal of the struct
+// type typ that initialize its slice fields, and those of its nested
+// structs, to empty slices. Pointer and map fields are left nil.
+func sliceInits(typ *Type, named map[string]*Type) string {
+	var b strings.Builder
+	for _, field := range typ.Children {
+		switch {
+		case field.Repeated > 0:
+			fmt.Fprintf(&b, "%s: %s{},\n", field.Name, field.GetTypeLiteral())
+		case field.Pointer || field.Map:
+		case field.Type == "struct":
+			if inits := sliceInits(field, named); inits != "" {
+				fmt.Fprintf(&b, "%s: %s{\n%s},\n", field.Name, field.GetTypeLiteral(), inits)
+			}
+		case named[field.Type] != nil:
+			if sliceInits(named[field.Type], named) != "" {
+				fmt.Fprintf(&b, "%s: *New%s(),\n", field.Name, field.Type)
+			}
+		}
+	}
+	return b.String()
+}
+
+// resetDecl returns a Reset method for the struct type typ. Assigning the
+// zero value covers every field, including inline structs, with nil slices
+// and pointers and zero scalars.
+func resetDecl(typ *Type) string {
+	return fmt.Sprintf("// Reset sets every field of %s to its zero value.\nfunc (v *%s) Reset() {\n*v = %s{}\n}", typ.Name, typ.Name, typ.Name)
+}
+
+func generateType(name string, value interface{}, cfg *Config) (*Type, error) {
+	result := &Type{Name: name, Config: cfg, Count: 1}
+	switch v := value.(type) {
+	case []interface{}:
+		types := make(map[reflect.Type]bool, 0)
+		for _, o := range v {
+			types[reflect.TypeOf(o)] = true
+		}
+		result.Repeated = 1
+		result.Singleton = len(v) == 1
+		if len(types) == 1 {
+			if cfg.PolymorphicField != "" {
+				variants, err := generateVariants(v, cfg.PolymorphicField, cfg)
+				if err != nil {
+					return nil, err
+				}
+				result.Variants = variants
+			}
+			// the element type is the union of every element.
+			t, err := generateType("", v[0], cfg)
+			if err != nil {
+				return nil, err
+			}
+			for _, o := range v[1:] {
+				t2, err := generateType("", o, cfg)
+				if err != nil {
+					return nil, err
+				}
+				if err := t.Merge(t2); err != nil {
+					return nil, fmt.Errorf("issue merging: %w", err)
+				}
+			}
+			result.Elems = t.Count
+			if t.Repeated > 0 {
+				result.Elems = t.Elems
+			}
+			result.Type = t.Type
+			result.Children = t.Children
+			result.Layout = t.Layout
+			result.Example = t.Example
+			result.Numbers, result.NumberCount = t.Numbers, t.NumberCount
+			result.Values, result.NonNumeric = t.Values, t.NonNumeric
+			result.Repeated = t.Repeated + 1
+			if cfg.ArrayDepth > 0 && result.Repeated > cfg.ArrayDepth {
+				// stop unwrapping arrays nested deeper than the cap.
+				result.Repeated = cfg.ArrayDepth
+				result.Type = "interface{}"
+				result.Children = nil
+			}
+		} else if len(types) > 1 {
+			result.Type = cfg.conflictType()
+			result.Elems = len(v)
+		} else {
+			result.Type = "interface{}"
+		}
+	case map[string]interface{}:
+		result.Type = "struct"
+		children, err := generateFieldTypes(v, cfg)
+		if err != nil {
+			return nil, err
+		}
+		result.Children = children
+	case string:
+		if embedded, ok := embeddedJSON(v, cfg); ok {
+			embeddedType, err := generateType(name, embedded, cfg)
+			if err != nil {
+				return nil, err
+			}
+			result = embeddedType
+			result.Layout = "json"
+			break
+		}
+		result.Type = "string"
+		if cfg.DetectConstants || cfg.EmitConstants {
+			result.Value = strconv.Quote(v)
+		}
+		if cfg.Enums > 0 {
+			result.Values = []string{v}
+		}
+		if cfg.recordExamples() {
+			result.Example = v
+		}
+		if cfg.FlexibleTypes && v != "" {
+			// the Flexible types decode the string's content as a number.
+			var f float64
+			result.NonNumeric = json.Unmarshal([]byte(v), &f) != nil
+		}
+		if cfg.DetectIP && net.ParseIP(v) != nil {
+			result.Type = "net.IP"
+		}
+		if cfg.DetectBytes && isBase64(v) {
+			result.Type = "[]byte"
+		}
+		if cfg.DetectUUID && uuidPattern.MatchString(v) {
+			// like time layouts, the layout is dropped when merged with a
+			// value without it.
+			result.Layout = "uuid"
+		}
+		for _, layout := range cfg.TimeLayouts {
+			if _, err := time.Parse(layout, v); err == nil {
+				if layout == time.RFC3339 || layout == time.RFC3339Nano {
+					// the only layouts time.Time unmarshals.
+					result.Type = "time.Time"
+				}
+				result.Layout = layout
+				break
+			}
+		}
+	case float64:
+		result.Type = "float64"
+		if cfg.IntInference && isInt64(v) {
+			result.Type = cfg.intType()
+		}
+		result.Layout = unixLayout(v, cfg.TimeUnix)
+		if cfg.DetectConstants || cfg.EmitConstants {
+			result.Value = strconv.FormatFloat(v, 'g', -1, 64)
+		}
+		if cfg.Enums > 0 && result.Type != "float64" {
+			result.Values = []string{strconv.FormatFloat(v, 'f', -1, 64)}
+		}
+		if cfg.recordExamples() {
+			result.Example = v
+		}
+		if cfg.Histogram != nil {
+			result.Numbers, result.NumberCount = []float64{v}, 1
+		}
+	case json.Number:
+		result.Type = "json.Number"
+		if f, err := v.Float64(); err == nil {
+			result.Layout = unixLayout(f, cfg.TimeUnix)
+			if cfg.Histogram != nil {
+				result.Numbers, result.NumberCount = []float64{f}, 1
+			}
+		}
+		if cfg.DetectConstants || cfg.EmitConstants {
+			result.Value = v.String()
+		}
+		if cfg.recordExamples() {
+			result.Example = v
+		}
+	case bool:
+		result.Type = "bool"
+		if cfg.DetectConstants || cfg.EmitConstants {
+			result.Value = strconv.FormatBool(v)
+		}
+		if cfg.recordExamples() {
+			result.Example = v
+		}
+	default:
+		if reflect.TypeOf(value) == nil {
+			result.Type = "interface{}"
+		} else {
+			result.Type = reflect.TypeOf(value).Name()
+		}
+	}
+	if value == nil {
+		result.Observed = map[string]int{"null": 1}
+	} else {
+		result.Observed = map[string]int{result.GetType(): 1}
+	}
+	return result, nil
+}
+
+// embeddedJSON returns the JSON object or array encoded in s, if
+// cfg.DetectJSONStrings is set and s holds one.
+func embeddedJSON(s string, cfg *Config) (interface{}, bool) {
+	if !cfg.DetectJSONStrings {
+		return nil, false
+	}
+	var v interface{}
+	if err := json.Unmarshal([]byte(s), &v); err != nil {
+		return nil, false
+	}
+	switch v.(type) {
+	case map[string]interface{}, []interface{}:
+		return v, true
+	}
+	return nil, false
+}
+
+// isInt64 reports whether v is an integer that fits in an int64.
+func isInt64(v float64) bool {
+	return v == math.Trunc(v) && v >= math.MinInt64 && v < math.MaxInt64
+}
+
+// unixLayout returns the Layout for a number that looks like a Unix timestamp
+// in the given unit, "seconds" or "millis", or an empty string if it doesn't.
+// Only integral timestamps between 2001 and 2096 are recognized.
+func unixLayout(v float64, unit string) string {
+	min, max := 1e9, 4e9
+	switch unit {
+	case "seconds":
+	case "millis":
+		min, max = min*1000, max*1000
+	default:
+		return ""
+	}
+	if v != math.Trunc(v) || v < min || v >= max {
+		return ""
+	}
+	return "unix " + unit
+}
+
+// annotateTimeLayouts comments the fields of typ, and of its nested structs,
+// whose values need converting after decoding: times in a layout other than
+// RFC 3339 and Unix timestamps.
+func annotateTimeLayouts(typ *Type) {
+	for _, field := range typ.Children {
+		layout := field.Layout
+		if field.Type == "interface{}" || field.Type == "json.RawMessage" {
+			// the values were also null or of another type.
+			layout = ""
+		}
+		switch layout {
+		case "", time.RFC3339, time.RFC3339Nano, "uuid", "json":
+		case "unix seconds", "unix millis":
+			field.addComment(fmt.Sprintf("Unix time in %s; use time.Unix to convert.", strings.TrimPrefix(field.Layout, "unix ")))
+		default:
+			field.addComment(fmt.Sprintf("Time with layout %q; use time.Parse to convert.", field.Layout))
+		}
+		annotateTimeLayouts(field)
+	}
+}
+
+// sortFields sorts the fields of typ, and of its nested structs and variants,
+// according to order. For the "first-record" order, keys gives the key order
+// of the first record; fields it doesn't list follow alphabetically.
+func sortFields(typ *Type, order string, keys *keyOrder) {
+	sort.SliceStable(typ.Children, func(i, j int) bool {
+		a, b := typ.Children[i], typ.Children[j]
+		switch order {
+		case "type-grouped":
+			if ca, cb := fieldCategory(a), fieldCategory(b); ca != cb {
+				return ca < cb
+			}
+		case "first-record":
+			if pa, pb := keys.position(a.Key), keys.position(b.Key); pa != pb {
+				return pa < pb
+			}
+		case "common-first-alpha":
+			if a.Count != b.Count {
+				return a.Count > b.Count
+			}
+			if la, lb := strings.ToLower(a.Key), strings.ToLower(b.Key); la != lb {
+				return la < lb
+			}
+		}
+		if a.Key != b.Key {
+			return a.Key < b.Key
+		}
+		return a.Name < b.Name
+	})
+	for _, field := range typ.Children {
+		sortFields(field, order, keys.field(field.Key))
+	}
+	for _, variant := range typ.Variants {
+		sortFields(variant, order, keys)
+	}
+}
+
+// fieldCategory returns the group a field belongs to in the "type-grouped"
+// field order: 0 for scalars, 1 for arrays and 2 for nested structs.
+func fieldCategory(t *Type) int {
+	switch {
+	case t.Repeated > 0 || t.Map:
+		return 1
+	case t.Type == "struct":
+		return 2
+	}
+	return 0
+}
+
+// detectMaps changes each field of typ, and of its nested structs, that is an
+// object with at least threshold fields keyed by IDs and sharing a type to a
+// map of the merged type of those fields.
+func detectMaps(typ *Type, threshold int) error {
+	for _, field := range typ.Children {
+		if err := detectMaps(field, threshold); err != nil {
+			return err
+		}
+		if field.Type != "struct" || len(field.Children) < threshold || !mapLike(field.Children) {
+			continue
+		}
+		value := field.Children[0].clone()
+		for _, v := range field.Children[1:] {
+			if err := value.Merge(v); err != nil {
+				return fmt.Errorf("issue with '%v': %w", field.Name, err)
+			}
+		}
+		field.Map, field.Elems = true, value.Count
+		field.Type, field.Children, field.Layout = value.Type, value.Children, value.Layout
+		field.Example, field.Numbers, field.Values = value.Example, value.Numbers, value.Values
+		field.NumberCount, field.NonNumeric = value.NumberCount, value.NonNumeric
+	}
+	return nil
+}
+
+// mapLike reports whether fields look like the entries of a map: each is
+// keyed by an ID, and all are non-null objects or scalars of one type.
+func mapLike(fields Fields) bool {
+	for _, field := range fields {
+		if !idLikeKey(field.Key) || field.Repeated > 0 || field.Observed["null"] > 0 || len(field.Variants) > 0 {
+			return false
+		}
+		first := fields[0].Type
+		if field.Type != first && !(numericTypes[field.Type] && numericTypes[first]) {
+			return false
+		}
+		if field.Type == field.Config.conflictType() {
+			return false
+		}
+	}
+	return true
+}
+
+// idLikeKey reports whether key looks like an ID rather than a name: it
+// holds a digit or a character other than letters, underscores and hyphens.
+func idLikeKey(key string) bool {
+	for _, r := range key {
+		if unicode.IsDigit(r) || !(unicode.IsLetter(r) || r == '_' || r == '-') {
+			return true
+		}
+	}
+	return false
+}
+
+// emptyObjectsAsMaps changes the type of each field of typ, and of its nested
+// structs, that has no fields of its own to map[string]interface{}.
+func emptyObjectsAsMaps(typ *Type) {
+	for _, field := range typ.Children {
+		if field.Type == "struct" && len(field.Children) == 0 {
+			field.Type = "map[string]interface{}"
+		}
+		emptyObjectsAsMaps(field)
+	}
+}
+
+// capDepth changes the type of each nested struct field more than depth
+// levels below typ to map[string]interface{}.
+func capDepth(typ *Type, depth int) {
+	for _, field := range typ.Children {
+		if field.Type != "struct" {
+			continue
+		}
+		if depth == 0 {
+			field.Type, field.Children = "map[string]interface{}", nil
+			continue
+		}
+		capDepth(field, depth-1)
+	}
+}
+
+// detectConstants comments each field of typ, and of its nested structs,
+// that held the same scalar value in more than one object. If emit is true,
+// those fields are instead removed and returned as const declarations named
+// after the path to the field, made unique against used.
+func detectConstants(typ *Type, path string, emit bool, used map[string]bool) []string {
+	var decls []string
+	fields := typ.Children[:0]
+	for _, field := range typ.Children {
+		if field.Value == "" || field.Count < 2 || field.Repeated > 0 || field.Map {
+			decls = append(decls, detectConstants(field, path+field.Name, emit, used)...)
+			fields = append(fields, field)
+			continue
+		}
+		if emit {
+			name := uniqueName(used, path+field.Name)
+			decls = append(decls, fmt.Sprintf("// %s is the constant value of the %q field.\nconst %s = %s",
+				name, field.Key, name, field.Value))
+			continue
+		}
+		field.addComment("constant: " + field.Value)
+		fields = append(fields, field)
+	}
+	typ.Children = fields
+	return decls
+}
+
+// enumDecls replaces the type of each string or small integer field of typ,
+// and of its nested structs, that held fewer than cfg.Enums distinct values,
+// some of them more than once, with a named type, named after the path to
+// the field, singular for arrays. Nullable fields wrap the named type.
+// It returns the declarations of those types and of a constant for each of
+// their values. String values are in the order they were first seen, and
+// integer values in numeric order. Type and constant names are made unique
+// against used, and added to it.
+func enumDecls(typ *Type, path string, cfg *Config, used map[string]bool) []string {
+	var decls []string
+	for _, field := range typ.Children {
+		if field.Type == "struct" {
+			decls = append(decls, enumDecls(field, path+field.Name, cfg, used)...)
+			continue
+		}
+		seen := field.Count - field.Observed["null"]
+		if field.Repeated > 0 || field.Map {
+			seen = field.Elems
+		}
+		if len(field.Values) == 0 || len(field.Values) >= cfg.Enums || seen <= len(field.Values) {
+			// too many values, or each seen only once.
+			continue
+		}
+		values := append([]string(nil), field.Values...)
+		base := field.Type
+		if strings.HasPrefix(base, "Nullable[") {
+			base = strings.TrimSuffix(strings.TrimPrefix(base, "Nullable["), "]")
+		}
+		switch {
+		case base == "string":
+			if cfg.Canonical {
+				sort.Strings(values)
+			}
+		case base == cfg.intType() && field.Layout == "" && smallInts(values):
+			sort.Slice(values, func(i, j int) bool {
+				a, _ := strconv.Atoi(values[i])
+				b, _ := strconv.Atoi(values[j])
+				return a < b
+			})
+		default:
+			continue
+		}
+		name := path + field.Name
+		if field.Repeated > 0 || field.Map {
+			name = path + singularize(field.Name)
+		}
+		name = uniqueName(used, name)
+		var consts strings.Builder
+		for _, v := range values {
+			constName, literal := name+strings.Replace(v, "-", "Neg", 1), v
+			if base == "string" {
+				constName, literal = name+enumConstSuffix(v, cfg.Initialisms), strconv.Quote(v)
+			}
+			constName = uniqueName(used, constName)
+			fmt.Fprintf(&consts, "%s %s = %s\n", constName, name, literal)
+		}
+		decls = append(decls, fmt.Sprintf("// %s is the type of the %q field.\ntype %s %s", name, field.Key, name, base),
+			fmt.Sprintf("// Values of %s observed in the input.\nconst (\n%s)", name, consts.String()))
+		field.Type = strings.Replace(field.Type, base, name, 1)
+	}
+	return decls
+}
+
+// smallInts reports whether each of values is an integer literal in the range
+// -100 to 100, small enough to be an enum value rather than a quantity.
+func smallInts(values []string) bool {
+	for _, v := range values {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < -100 || n > 100 {
+			return false
+		}
+	}
+	return true
+}
+
+// enumConstSuffix returns the suffix of the name of the constant for the enum
+// value v: its words formatted as a field name, keeping a leading digit, or
+// Empty if v has no words.
+//
+// Example:
+// 	enumConstSuffix("in-progress", nil)
+// Output: InProgress
+func enumConstSuffix(v string, initialisms []string) string {
+	words := splitWords(v)
+	if len(words) == 0 {
+		return "Empty"
+	}
+	suffix := []rune(fmtFieldName(strings.Join(words, "_"), initialisms))
+	suffix[0] = []rune(words[0])[0]
+	if unicode.IsLetter(suffix[0]) {
+		suffix[0] = unicode.ToUpper(suffix[0])
+	}
+	return string(suffix)
+}
+
+// nullableDecl declares the generic wrapper used for nullable fields.
+const nullableDecl = `// Nullable holds a JSON value that may be null or missing. Valid reports
+// whether a value was present. An invalid Nullable is encoded as null, so a
+// missing field round-trips as an explicit null.
+type Nullable[T any] struct {
+	Value T
+	Valid bool
+}
+
+func (n *Nullable[T]) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		*n = Nullable[T]{}
+		return nil
+	}
+	if err := json.Unmarshal(data, &n.Value); err != nil {
+		return err
+	}
+	n.Valid = true
+	return nil
+}
+
+func (n Nullable[T]) MarshalJSON() ([]byte, error) {
+	if !n.Valid {
+		return []byte("null"), nil
+	}
+	return json.Marshal(n.Value)
+}`
+
+// flexibleTypes changes the type of each scalar field of typ, and of its
+// nested structs, that was seen both as a number and as strings that all
+// hold numbers to the Flexible type of its number type. It returns the sorted
+// number types used.
+func flexibleTypes(typ *Type) []string {
+	used := map[string]bool{}
+	var walk func(typ *Type)
+	walk = func(typ *Type) {
+		for _, field := range typ.Children {
+			walk(field)
+			if field.Repeated > 0 {
+				continue
+			}
+			base, seenString, ok := "", false, true
+			for t := range field.Observed {
+				switch {
+				case t == "null":
+				case stringTypes[t]:
+					seenString = true
+				case numericTypes[t]:
+					if base != "" && base != t {
+						// differing numeric types widen to float64.
+						t = "float64"
+					}
+					base = t
+				default:
+					ok = false
+				}
+			}
+			if ok && seenString && base != "" && !field.NonNumeric {
+				field.Type = flexibleName(base)
+				used[base] = true
+			}
+		}
+	}
+	walk(typ)
+	var result []string
+	for base := range used {
+		result = append(result, base)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// flexibleName returns the name of the Flexible type of the number type base.
+func flexibleName(base string) string {
+	return "Flexible" + strings.Title(base)
+}
+
+// flexibleDecl declares the Flexible type of the number type base.
+func flexibleDecl(base string) string {
+	name := flexibleName(base)
+	return fmt.Sprintf(`// %s decodes a JSON number, or a JSON string holding one, into its
+// underlying type %s. An empty string decodes as zero.
+type %s %s
+
+// UnmarshalJSON decodes a JSON number or numeric string into f.
+func (f *%s) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		if s == "" {
+			*f = 0
+			return nil
+		}
+		data = []byte(s)
+	}
+	var v %s
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	*f = %s(v)
+	return nil
+}`, name, base, name, base, name, base, name)
+}
+
+// minBase64Len is the length below which strings aren't taken to be base64,
+// since short alphanumeric strings often happen to be valid base64.
+const minBase64Len = 16
+
+// uuidPattern matches UUIDs in their canonical 8-4-4-4-12 hex form.
+var uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// jsonStringDecls replaces the type of each field of typ, and of its nested
+// structs, whose string values held embedded JSON with a named type, named
+// after the path to the field, whose methods decode and encode it as a JSON
+// string. It returns the declarations of those types and the types. Type
+// names are made unique against used, and added to it.
+func jsonStringDecls(typ *Type, path string, used map[string]bool) ([]string, []*Type) {
+	var decls []string
+	var types []*Type
+	for _, field := range typ.Children {
+		d, ts := jsonStringDecls(field, path+field.Name, used)
+		decls, types = append(decls, d...), append(types, ts...)
+		if field.Layout != "json" {
+			continue
+		}
+		name := uniqueName(used, path+field.Name)
+		t := &Type{Name: name, Type: field.Type, Repeated: field.Repeated, Map: field.Map,
+			Children: field.Children, Config: field.Config}
+		decls = append(decls, fmt.Sprintf(`// %s holds the JSON encoded in the %q string field.
+type %s %s
+
+// UnmarshalJSON decodes the JSON string holding v.
+func (v *%s) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	type plain %s
+	return json.Unmarshal([]byte(s), (*plain)(v))
+}
+
+// MarshalJSON encodes v as a JSON string.
+func (v %s) MarshalJSON() ([]byte, error) {
+	type plain %s
+	data, err := json.Marshal(plain(v))
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(string(data))
+}`, name, field.Key, name, t.GetTypeLiteral(), name, name, name, name))
+		types = append(types, t)
+		field.Type, field.Repeated, field.Map, field.Children, field.Layout = name, 0, false, nil, ""
+	}
+	return decls, types
+}
+
+// setUUIDTypes changes the type of each string field of typ, and of its
+// nested structs, whose every non-null value was a UUID to uuidType, and
+// that of each such Nullable[string] field to Nullable of uuidType. It reports
+// whether any field was changed.
+func setUUIDTypes(typ *Type, uuidType string) bool {
+	changed := false
+	for _, field := range typ.Children {
+		if setUUIDTypes(field, uuidType) {
+			changed = true
+		}
+		if field.Layout != "uuid" {
+			continue
+		}
+		switch field.Type {
+		case "string":
+			field.Type, field.Layout = uuidType, ""
+			changed = true
+		case "Nullable[string]":
+			field.Type, field.Layout = "Nullable["+uuidType+"]", ""
+			changed = true
+		}
+	}
+	for _, variant := range typ.Variants {
+		if setUUIDTypes(variant, uuidType) {
+			changed = true
+		}
+	}
+	return changed
+}
+
+// isBase64 reports whether s looks like standard base64 encoded binary data:
+// it decodes, is at least minBase64Len long, and holds padding, '+' or '/',
+// or a mix of upper case, lower case and digits, which rules out words and
+// hex strings.
+func isBase64(s string) bool {
+	if len(s) < minBase64Len {
+		return false
+	}
+	if _, err := base64.StdEncoding.DecodeString(s); err != nil {
+		return false
+	}
+	if strings.ContainsAny(s, "+/=") {
+		return true
+	}
+	var upper, lower, digit bool
+	for _, r := range s {
+		upper = upper || unicode.IsUpper(r)
+		lower = lower || unicode.IsLower(r)
+		digit = digit || unicode.IsDigit(r)
+	}
+	return upper && lower && digit
+}
+
+// nullableTypes are the scalar types wrapped in Nullable.
+var nullableTypes = map[string]bool{
+	"string":      true,
+	"float64":     true,
+	"int64":       true,
+	"int":         true,
+	"bool":        true,
+	"net.IP":      true,
+	"time.Time":   true,
+	"json.Number": true,
+}
+
+// wrapNullable changes the type of each scalar field of typ, and of its
+// nested structs, that was null or missing in some objects to Nullable of
+// its type. Fields seen as null and one scalar type are wrapped as that
+// type. It reports whether any field was wrapped.
+func wrapNullable(typ *Type) bool {
+	wrapped := false
+	for _, field := range typ.Children {
+		if field.Type == "struct" && wrapNullable(field) {
+			// the fields of other types, such as a struct seen as null too,
+			// aren't emitted.
+			wrapped = true
+		}
+		if field.Repeated > 0 || field.Map || (field.Count == typ.objects() && field.Observed["null"] == 0) {
+			continue
+		}
+		base := field.Type
+		if !nullableTypes[base] {
+			base = ""
+			if observed := field.nonNullTypes(); len(observed) == 1 && nullableTypes[observed[0]] {
+				base = observed[0]
+			}
+		}
+		if base != "" {
+			field.Type = "Nullable[" + base + "]"
+			wrapped = true
+		}
+	}
+	return wrapped
+}
+
+// markRareDeprecated adds a deprecation comment to each field of typ, and of
+// its nested structs, that is present in less than threshold of the objects
+// it could have appeared in.
+func markRareDeprecated(typ *Type, threshold float64) {
+	for _, field := range typ.Children {
+		if ratio := float64(field.Count) / float64(typ.objects()); ratio < threshold {
+			field.addComment(fmt.Sprintf("Deprecated: seen in only %d of %d records (%.1f%%)",
+				field.Count, typ.objects(), ratio*100))
+		}
+		markRareDeprecated(field, threshold)
+	}
+}
+
+// missingFields returns the paths of the fields of typ, and of its nested
+// structs, that were missing from some of the objects they could have
+// appeared in, with how many objects they were seen in. Fields whose JSON key
+// is listed in optional are allowed to be missing.
+func missingFields(typ *Type, path string, optional []string) []string {
+	var missing []string
+	for _, field := range typ.Children {
+		fieldPath := path + "." + field.Name
+		if field.Count < typ.objects() && !containsKey(optional, field.Key) {
+			missing = append(missing, fmt.Sprintf("%s (%d of %d)", fieldPath, field.Count, typ.objects()))
+		}
+		missing = append(missing, missingFields(field, fieldPath, optional)...)
+	}
+	return missing
+}
+
+// markNullHeavy changes the type of each field of typ, and of its nested
+// structs, that was null in more than threshold of the objects it could have
+// appeared in to the type of its non-null values, if there was only one.
+// Scalars and structs become pointers, and slices stay nil when null.
+func markNullHeavy(typ *Type, threshold float64) {
+	for _, field := range typ.Children {
+		markNullHeavy(field, threshold)
+		nulls := field.Observed["null"]
+		ratio := float64(nulls) / float64(typ.objects())
+		if nulls == 0 || ratio <= threshold || !setNonNullType(field) {
+			continue
+		}
+		field.Pointer = field.Repeated == 0 && !field.Map
+		field.addComment(fmt.Sprintf("Null in %d of %d records (%.1f%%).", nulls, typ.objects(), ratio*100))
+	}
+}
+
+// pointersForOptional makes each scalar or struct field of typ, and of its
+// nested structs, a pointer if it was missing from some of the objects it
+// could have appeared in or was null. Fields that were null and one other
+// type take that type.
+func pointersForOptional(typ *Type) {
+	for _, field := range typ.Children {
+		pointersForOptional(field)
+		if field.Observed["null"] > 0 && !setNonNullType(field) {
+			continue
+		}
+		if field.Count == typ.objects() && field.Observed["null"] == 0 {
+			continue
+		}
+		if field.Repeated == 0 && !field.Map && (field.Type == "struct" || nullableTypes[field.Type]) {
+			field.Pointer = true
+		}
+	}
+}
+
+// unwrapSingletons removes a slice dimension from each field of typ, and of
+// its nested structs, that was always an array of exactly one element.
+func unwrapSingletons(typ *Type) {
+	for _, field := range typ.Children {
+		unwrapSingletons(field)
+		if field.Repeated > 0 && field.Singleton {
+			field.Repeated--
+		}
+	}
+}
+
+// keepRequiredEmpty drops omitempty from each field of typ, and of its nested
+// structs, that was present and non-null in every object it could have
+// appeared in.
+func keepRequiredEmpty(typ *Type) {
+	for _, field := range typ.Children {
+		keepRequiredEmpty(field)
+		if field.Count == typ.objects() && field.Observed["null"] == 0 {
+			field.KeepEmpty = true
+		}
+	}
+}
+
+// setNonNullType sets the type of field, which was observed as null, to the
+// type of its non-null values. It reports false, leaving field unchanged, if
+// there wasn't exactly one such type or its shape wasn't kept.
+func setNonNullType(field *Type) bool {
+	observed := field.nonNullTypes()
+	if len(observed) != 1 {
+		return false
+	}
+	base, repeated := splitObserved(observed[0])
+	if base == "struct" && len(field.Children) == 0 {
+		return false
+	}
+	field.Type, field.Repeated = base, repeated
+	return true
+}
+
+func generateFieldTypes(obj map[string]interface{}, cfg *Config) ([]*Type, error) {
+	result := []*Type{}
+
+	keys := make([]string, 0, len(obj))
+	for key := range obj {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	canonical := map[string]*Type{}
+	for _, key := range keys {
+		typ, err := generateType(key, obj[key], cfg)
+		if err != nil {
+			return nil, fmt.Errorf("issue with '%v': %w", key, err)
+		}
+		if cfg.isRawField(key) {
+			typ = &Type{Type: "json.RawMessage", Config: cfg, Count: 1,
+				Observed: map[string]int{"json.RawMessage": 1}}
+		}
+		if pinned, _, ok := cfg.fieldType(key); ok {
+			typ = &Type{Type: pinned, Config: cfg, Count: 1,
+				Observed: map[string]int{pinned: 1}}
+		}
+		if numericTypes[typ.Type] && cfg.isJSONNumberField(key) {
+			typ.Type = "json.Number"
+			typ.Observed = map[string]int{typ.GetType(): 1}
+		}
+		typ.Keys = map[string]int{key: 1}
+		setFieldKey(typ, key, cfg)
+		if cfg.CanonicalizeKeys {
+			// merge spelling variants of the same key within a single object.
+			if field, ok := canonical[canonicalKey(key)]; ok {
+				if err := field.Merge(typ); err != nil {
+					return nil, fmt.Errorf("issue with '%v': %w", key, err)
+				}
+				field.Count = 1
+				continue
+			}
+			canonical[canonicalKey(key)] = typ
+		}
+		result = append(result, typ)
+	}
+	return result, nil
+}
+
+// setFieldKey sets the JSON key of the field typ, along with the field name
+// and tags derived from it.
+func setFieldKey(typ *Type, key string, cfg *Config) {
+	typ.Key = key
+	typ.Name = cfg.fieldName(key)
+	tag := fmtTagName(key, cfg.TagCase)
+	typ.Tags = nil
+	// if we need to rewrite the field name we need to record the json field in a tag.
+	if cfg.hasTag("json") && (typ.Name != tag || len(cfg.Tags) > 1) {
+		typ.Tags = map[string]string{"json": tag}
+	}
+	if cfg.hasTag("yaml") {
+		if typ.Tags == nil {
+			typ.Tags = map[string]string{}
+		}
+		typ.Tags["yaml"] = strings.ToLower(key)
+	}
+	if cfg.hasTag("bson") {
+		if typ.Tags == nil {
+			typ.Tags = map[string]string{}
+		}
+		typ.Tags["bson"] = key
+	}
+}
+
+// canonicalKey returns key lowercased and stripped of underscores, hyphens
+// and spaces, so that spelling variants of a key compare equal.
+func canonicalKey(key string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case '_', '-', ' ':
+			return -1
+		}
+		return unicode.ToLower(r)
+	}, key)
+}
+
+// canonicalizeKeys renames each field of typ, and of its nested structs, that
+// was seen under several spellings to its most frequent spelling. Merged
+// spellings are reported to w if it is non-nil.
+func canonicalizeKeys(typ *Type, path string, w io.Writer) {
+	for _, field := range typ.Children {
+		if len(field.Keys) > 1 {
+			spellings := make([]string, 0, len(field.Keys))
+			for key := range field.Keys {
+				spellings = append(spellings, key)
+			}
+			sort.Slice(spellings, func(i, j int) bool {
+				ci, cj := field.Keys[spellings[i]], field.Keys[spellings[j]]
+				if ci != cj {
+					return ci > cj
+				}
+				return spellings[i] < spellings[j]
+			})
+			setFieldKey(field, spellings[0], field.Config)
+			if w != nil {
+				parts := make([]string, 0, len(spellings))
+				for _, key := range spellings {
+					parts = append(parts, fmt.Sprintf("%q (%d)", key, field.Keys[key]))
+				}
+				fmt.Fprintf(w, "%s.%s: merged keys %s into %q\n", path, field.Name, strings.Join(parts, ", "), spellings[0])
+			}
+		}
+		canonicalizeKeys(field, path+"."+field.Name, w)
+	}
+}
+
+func renderTypes(types []Type, depth int, cfg *Config) string {
+	result := "struct {"
+
+	for _, typ := range types {
+		result += fmt.Sprintf("%v %v %v\n", typ.Name, typ.GetType(), typ.GetTags())
+	}
+	return result
+}
+
+// mapValueName returns the name of the value type of a map type named name:
+// the singular of name if it is plural, and name with a "Value" suffix
+// otherwise.
+//
+// Example:
+// 	mapValueName("Users")
+// Output: User
+func mapValueName(name string) string {
+	if singular := singularize(name); singular != name {
+		return singular
+	}
+	return name + "Value"
+}
+
+// singularize returns the singular form of the English plural at the end of
+// s, or s unchanged if it doesn't look plural.
+func singularize(s string) string {
+	lower := strings.ToLower(s)
+	for _, suffix := range []string{"data", "news", "series", "species", "status", "ss", "us", "is"} {
+		if strings.HasSuffix(lower, suffix) {
+			return s
+		}
+	}
+	for _, irregular := range [][2]string{{"people", "person"}, {"children", "child"}, {"men", "man"}} {
+		if strings.HasSuffix(lower, irregular[0]) {
+			n := len(s) - len(irregular[0])
+			return s[:n+1] + irregular[1][1:]
+		}
+	}
+	switch {
+	case strings.HasSuffix(lower, "ies") && len(s) > 3:
+		return s[:len(s)-3] + "y"
+	case strings.HasSuffix(lower, "sses"), strings.HasSuffix(lower, "xes"),
+		strings.HasSuffix(lower, "ches"), strings.HasSuffix(lower, "shes"):
+		return s[:len(s)-2]
+	case strings.HasSuffix(lower, "s") && len(s) > 1:
+		return s[:len(s)-1]
+	}
+	return s
+}
+
+// commonInitialisms is the set of lowercase words emitted in upper case when
+// they make up a part of a field name, from golint.
+var commonInitialisms = map[string]bool{
+	"acl": true, "api": true, "ascii": true, "cpu": true, "css": true, "dns": true,
+	"eof": true, "guid": true, "html": true, "http": true, "https": true, "id": true,
+	"ip": true, "json": true, "lhs": true, "qps": true, "ram": true, "rhs": true,
+	"rpc": true, "sla": true, "smtp": true, "sql": true, "ssh": true, "tcp": true,
+	"tls": true, "ttl": true, "udp": true, "ui": true, "uid": true, "uuid": true,
+	"uri": true, "url": true, "utf8": true, "vm": true, "xml": true, "xmpp": true,
+	"xsrf": true, "xss": true,
+}
+
+// fmtFieldName formats a string as a struct key. Each underscore separated
+// part that is a common initialism, or one of extra, is upper cased.
+//
+// Example:
+// 	fmtFieldName("http_api_id", nil)
+// Output: HTTPAPIID
+func fmtFieldName(s string, extra []string) string {
+	parts := strings.Split(s, "_")
+	for i, part := range parts {
+		initialism := commonInitialisms[strings.ToLower(part)]
+		for _, word := range extra {
+			initialism = initialism || strings.EqualFold(word, part)
+		}
+		if initialism {
+			parts[i] = strings.ToUpper(part)
+			continue
+		}
+		parts[i] = strings.Title(part)
+	}
+	assembled := strings.Join(parts, "")
+	runes := []rune(assembled)
+	for i, c := range runes {
+		ok := unicode.IsLetter(c) || unicode.IsDigit(c)
+		if i == 0 {
+			ok = unicode.IsLetter(c)
+		}
+		if !ok {
+			runes[i] = '_'
+		}
+	}
+	return string(runes)
+}
+
+// fieldName returns the name of fields with the JSON key key according to
+// c.NameCase.
+//
+// Example:
+// 	fieldName("userName") with NameCase "screaming-snake"
+// Output: USER_NAME
+func (c *Config) fieldName(key string) string {
+	switch c.NameCase {
+	case "preserve":
+		runes := []rune(key)
+		for i, r := range runes {
+			if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+				runes[i] = '_'
+			}
+		}
+		if len(runes) > 0 {
+			runes[0] = unicode.ToUpper(runes[0])
+		}
+		return exportedName(string(runes))
+	case "screaming-snake":
+		words := splitWords(key)
+		if len(words) == 0 {
+			return fmtFieldName(key, nil)
+		}
+		return exportedName(strings.ToUpper(strings.Join(words, "_")))
+	}
+	return fmtFieldName(key, c.Initialisms)
+}
+
+// exportedName returns name, prefixed with "X" if it doesn't start with an
+// upper case letter, so that it names an exported field.
+//
+// Example:
+// 	exportedName("_id")
+// Output: X_id
+func exportedName(name string) string {
+	if r := []rune(name); len(r) > 0 && unicode.IsUpper(r[0]) {
+		return name
+	}
+	return "X" + name
+}
+
+// fmtTagName formats a JSON key for use as a struct tag name according to
+// tagCase. The key is returned unchanged for the "original" case.
+//
+// Example:
+// 	fmtTagName("fooBar", "snake")
+// Output: foo_bar
+func fmtTagName(key, tagCase string) string {
+	words := splitWords(key)
+	if len(words) == 0 {
+		return key
+	}
+	switch tagCase {
+	case "snake":
+		return strings.Join(words, "_")
+	case "kebab":
+		return strings.Join(words, "-")
+	case "camel":
+		for i := 1; i < len(words); i++ {
+			words[i] = strings.Title(words[i])
+		}
+		return strings.Join(words, "")
+	}
+	return key
+}
+
+// splitWords splits s into lowercase words on non-alphanumeric characters and
+// on camelCase boundaries ("HTTPServerID" splits into http, server and id).
+func splitWords(s string) []string {
+	var words []string
+	var word []rune
+	runes := []rune(s)
+	flush := func() {
+		if len(word) > 0 {
+			words = append(words, strings.ToLower(string(word)))
+			word = word[:0]
+		}
+	}
+	for i, c := range runes {
+		if !unicode.IsLetter(c) && !unicode.IsDigit(c) {
+			flush()
+			continue
+		}
+		if unicode.IsUpper(c) && len(word) > 0 {
+			prev := word[len(word)-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if !unicode.IsUpper(prev) || nextLower {
+				flush()
+			}
+		}
+		word = append(word, c)
+	}
+	flush()
+	return words
+}
@@ -0,0 +1,132 @@
+package jsonstruct
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// pathStep is one step of a JSONPath expression: a member name, an array
+// index, or a wildcard matching every member or element.
+type pathStep struct {
+	key      string
+	index    int
+	isIndex  bool
+	wildcard bool
+}
+
+// jsonPathSteps describes the steps of the JSONPath subset parseJSONPath
+// supports, for error messages.
+const jsonPathSteps = `.name, ['name'], .*, [*] and [n]`
+
+// parseJSONPath parses the subset of JSONPath made of a leading "$" followed
+// by ".name", ".*", "['name']", "[n]" and "[*]" steps. Recursive descent,
+// filters, slices and unions are reported as unsupported.
+func parseJSONPath(path string) ([]pathStep, error) {
+	if !strings.HasPrefix(path, "$") {
+		return nil, fmt.Errorf("jsonpath %q must start with $", path)
+	}
+	var steps []pathStep
+	rest := path[1:]
+	for rest != "" {
+		switch rest[0] {
+		case '.':
+			rest = rest[1:]
+			if strings.HasPrefix(rest, ".") {
+				return nil, fmt.Errorf("jsonpath %q: recursive descent (..) is not supported; use %s steps", path, jsonPathSteps)
+			}
+			end := strings.IndexAny(rest, ".[")
+			if end < 0 {
+				end = len(rest)
+			}
+			name := rest[:end]
+			rest = rest[end:]
+			switch name {
+			case "":
+				return nil, fmt.Errorf("jsonpath %q has an empty member name", path)
+			case "*":
+				steps = append(steps, pathStep{wildcard: true})
+			default:
+				steps = append(steps, pathStep{key: name})
+			}
+		case '[':
+			end := strings.Index(rest, "]")
+			if end < 0 {
+				return nil, fmt.Errorf("jsonpath %q has an unclosed [", path)
+			}
+			sel := strings.TrimSpace(rest[1:end])
+			rest = rest[end+1:]
+			quoted := len(sel) >= 2 && (sel[0] == '\'' || sel[0] == '"') && strings.IndexByte(sel[1:], sel[0]) == len(sel)-2
+			switch {
+			case strings.HasPrefix(sel, "?"):
+				return nil, fmt.Errorf("jsonpath %q: filter expressions are not supported; use %s steps", path, jsonPathSteps)
+			case !quoted && strings.Contains(sel, ":"):
+				return nil, fmt.Errorf("jsonpath %q: array slices are not supported; use %s steps", path, jsonPathSteps)
+			case !quoted && strings.Contains(sel, ","):
+				return nil, fmt.Errorf("jsonpath %q: unions are not supported; use %s steps", path, jsonPathSteps)
+			case sel == "*":
+				steps = append(steps, pathStep{wildcard: true})
+			case quoted:
+				steps = append(steps, pathStep{key: sel[1 : len(sel)-1]})
+			default:
+				i, err := strconv.Atoi(sel)
+				if err != nil {
+					return nil, fmt.Errorf("jsonpath %q has an invalid selector [%s]", path, sel)
+				}
+				steps = append(steps, pathStep{index: i, isIndex: true})
+			}
+		default:
+			return nil, fmt.Errorf("jsonpath %q has an unexpected %q", path, rest[0])
+		}
+	}
+	return steps, nil
+}
+
+// selectJSONPath returns the values in v matched by the JSONPath expression
+// path. Wildcards match object members in key order.
+func selectJSONPath(v interface{}, path string) ([]interface{}, error) {
+	steps, err := parseJSONPath(path)
+	if err != nil {
+		return nil, err
+	}
+	nodes := []interface{}{v}
+	for _, step := range steps {
+		var next []interface{}
+		for _, node := range nodes {
+			switch node := node.(type) {
+			case map[string]interface{}:
+				switch {
+				case step.wildcard:
+					keys := make([]string, 0, len(node))
+					for key := range node {
+						keys = append(keys, key)
+					}
+					sort.Strings(keys)
+					for _, key := range keys {
+						next = append(next, node[key])
+					}
+				case !step.isIndex:
+					if value, ok := node[step.key]; ok {
+						next = append(next, value)
+					}
+				}
+			case []interface{}:
+				switch {
+				case step.wildcard:
+					next = append(next, node...)
+				case step.isIndex:
+					i := step.index
+					if i < 0 {
+						i += len(node)
+					}
+					if i >= 0 && i < len(node) {
+						next = append(next, node[i])
+					}
+				}
+			}
+		}
+		nodes = next
+	}
+	return nodes, nil
+}
@@ -0,0 +1,149 @@
+package jsonstruct
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"time"
+)
+
+// jsonSchemaDraft is the $schema of generated JSON Schemas.
+const jsonSchemaDraft = "http://json-schema.org/draft-07/schema#"
+
+// jsonSchema is a JSON Schema, holding the keywords generated schemas use.
+type jsonSchema struct {
+	Schema               string            `json:"$schema,omitempty"`
+	Title                string            `json:"title,omitempty"`
+	Type                 interface{}       `json:"type,omitempty"`
+	Format               string            `json:"format,omitempty"`
+	ContentEncoding      string            `json:"contentEncoding,omitempty"`
+	Properties           *schemaProperties `json:"properties,omitempty"`
+	Required             []string          `json:"required,omitempty"`
+	AdditionalProperties *jsonSchema       `json:"additionalProperties,omitempty"`
+	Items                *jsonSchema       `json:"items,omitempty"`
+	OneOf                []*jsonSchema     `json:"oneOf,omitempty"`
+}
+
+// schemaProperties holds the properties of an object schema, encoded in the
+// order of the fields they describe.
+type schemaProperties struct {
+	keys    []string
+	schemas []*jsonSchema
+}
+
+func (p *schemaProperties) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString("{")
+	for i, key := range p.keys {
+		if i > 0 {
+			buf.WriteString(",")
+		}
+		k, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		v, err := json.Marshal(p.schemas[i])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(k)
+		buf.WriteString(":")
+		buf.Write(v)
+	}
+	buf.WriteString("}")
+	return buf.Bytes(), nil
+}
+
+// renderJSONSchema renders typ as a draft-07 JSON Schema titled name.
+// container is "[]" or "map[string]" if the root JSON value is an array or
+// map of typ.
+func renderJSONSchema(typ *Type, name, container string) ([]byte, error) {
+	schema := schemaFor(typ)
+	switch container {
+	case "[]":
+		schema = &jsonSchema{Type: "array", Items: schema}
+	case "map[string]":
+		schema = &jsonSchema{Type: "object", AdditionalProperties: schema}
+	}
+	schema.Schema, schema.Title = jsonSchemaDraft, name
+	data, err := json.MarshalIndent(schema, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+// schemaFor returns the schema for values of typ, including its slice
+// dimensions.
+func schemaFor(typ *Type) *jsonSchema {
+	schema := elemSchema(typ)
+	if typ.Map {
+		schema = &jsonSchema{Type: "object", AdditionalProperties: schema}
+	}
+	for i := 0; i < typ.Repeated; i++ {
+		schema = &jsonSchema{Type: "array", Items: schema}
+	}
+	return schema
+}
+
+// elemSchema returns the schema for typ ignoring its slice dimensions.
+func elemSchema(typ *Type) *jsonSchema {
+	if len(typ.Variants) > 1 && typ.Config.PolymorphicField != "" {
+		schema := &jsonSchema{}
+		for _, variant := range typ.Variants {
+			schema.OneOf = append(schema.OneOf, objectSchema(variant))
+		}
+		return schema
+	}
+	switch typ.Type {
+	case "struct":
+		return objectSchema(typ)
+	case "string", "net.IP":
+		return &jsonSchema{Type: "string"}
+	case "time.Time":
+		if typ.Layout == time.RFC3339 || typ.Layout == time.RFC3339Nano {
+			return &jsonSchema{Type: "string", Format: "date-time"}
+		}
+		return &jsonSchema{Type: "string"}
+	case "[]byte":
+		return &jsonSchema{Type: "string", ContentEncoding: "base64"}
+	case "float64", "json.Number":
+		return &jsonSchema{Type: "number"}
+	case "int64", "int":
+		return &jsonSchema{Type: "integer"}
+	case "bool":
+		return &jsonSchema{Type: "boolean"}
+	case "map[string]interface{}":
+		return &jsonSchema{Type: "object"}
+	}
+	// fall back to the single non-null type the value was observed as.
+	observed := typ.nonNullTypes()
+	switch {
+	case len(observed) == 0 && typ.Observed["null"] > 0:
+		return &jsonSchema{Type: "null"}
+	case len(observed) == 1 && !strings.Contains(observed[0], "struct") && observed[0] != typ.Type:
+		base, _ := splitObserved(observed[0])
+		return elemSchema(&Type{Type: base, Config: typ.Config})
+	}
+	// any value.
+	return &jsonSchema{}
+}
+
+// objectSchema returns an object schema for the struct type typ. Fields
+// present in every object are required, and fields observed as null also
+// accept null.
+func objectSchema(typ *Type) *jsonSchema {
+	schema := &jsonSchema{Type: "object", Properties: &schemaProperties{}}
+	for _, field := range typ.Children {
+		fieldSchema := schemaFor(field)
+		if t, ok := fieldSchema.Type.(string); ok && t != "null" && field.Observed["null"] > 0 {
+			fieldSchema.Type = []string{t, "null"}
+		}
+		schema.Properties.keys = append(schema.Properties.keys, field.Key)
+		schema.Properties.schemas = append(schema.Properties.schemas, fieldSchema)
+		if field.Count >= typ.objects() {
+			schema.Required = append(schema.Required, field.Key)
+		}
+	}
+	return schema
+}
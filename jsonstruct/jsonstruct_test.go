@@ -0,0 +1,792 @@
+package jsonstruct
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io"
+	"io/ioutil"
+	"math"
+	"net"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// If `-write-golden` is provided when running tests, golden files are written.
+var writeGolden = false
+
+func TestMain(m *testing.M) {
+	var flagWriteGolden = flag.Bool("write-golden", false, "If true, writes out golden files")
+	flag.Parse()
+	if *flagWriteGolden {
+		writeGolden = true
+	}
+	os.Exit(m.Run())
+}
+
+func TestGenerate(t *testing.T) {
+	// t.Parallel()
+
+	tests := []struct {
+		name    string
+		input   string // input file name without extension, defaults to name
+		ext     string // input file extension, defaults to ".json"
+		cfg     *Config
+		golden  string // golden file extension, defaults to ".go"
+		wantErr bool
+	}{
+		{name: "empty", wantErr: true},
+		{name: "test_simple_json"},
+		{name: "test_nested_json"},
+		{name: "test_nullable_json"},
+		{name: "test_repeated_json"},
+		{name: "test_gzip", ext: ".json.gz"},
+		{name: "test_simple_array"},
+		{name: "test_invalid_field_chars"},
+		{name: "more_complex_example"},
+		{name: "test_tag_case_snake", input: "test_tag_case", cfg: &Config{OmitEmpty: true, TagCase: "snake"}},
+		{name: "test_tag_case_camel", input: "test_tag_case", cfg: &Config{OmitEmpty: true, TagCase: "camel"}},
+		{name: "test_tag_case_kebab", input: "test_tag_case", cfg: &Config{OmitEmpty: true, TagCase: "kebab"}},
+		{name: "test_ndjson"},
+		{name: "test_invalid_json", wantErr: true},
+		{name: "test_root_alias", input: "test_repeated_json", cfg: &Config{OmitEmpty: true, RootAlias: true}},
+		{name: "test_root_alias_scalars", cfg: &Config{OmitEmpty: true, RootAlias: true}},
+		{name: "test_top_level_map", cfg: &Config{OmitEmpty: true, TopLevelMap: true}},
+		{name: "test_top_level_map_users", input: "test_top_level_map", cfg: &Config{OmitEmpty: true, TopLevelMap: true}},
+		{name: "test_rare_deprecated", input: "test_repeated_json", cfg: &Config{OmitEmpty: true, RareDeprecated: 0.3}},
+		{name: "test_canonicalize_keys", cfg: &Config{OmitEmpty: true, CanonicalizeKeys: true}},
+		{name: "test_gen_constructor", input: "test_nullable_json", cfg: &Config{OmitEmpty: true, GenConstructor: true}},
+		{name: "test_gen_constructor_nested", cfg: &Config{OmitEmpty: true, IntInference: true, GenConstructor: true}},
+		{name: "test_gen_constructor_named", input: "test_gen_constructor_nested", cfg: &Config{OmitEmpty: true, IntInference: true, GenConstructor: true, NamedNested: true}},
+		{name: "test_detect_ip", cfg: &Config{OmitEmpty: true, DetectIP: true}},
+		{name: "test_polymorphic", cfg: &Config{OmitEmpty: true, PolymorphicField: "type"}},
+		{name: "test_polymorphic_collision", cfg: &Config{OmitEmpty: true, IntInference: true, PolymorphicField: "type"}},
+		{name: "test_polymorphic_empty", cfg: &Config{OmitEmpty: true, IntInference: true, PolymorphicField: "type"}},
+		{name: "test_adversarial_keys"},
+		{name: "test_time_layouts", cfg: &Config{OmitEmpty: true, TimeLayouts: []string{time.RFC3339, "2006/01/02 15:04"}, TimeUnix: "seconds"}},
+		{name: "test_tag_quote_double", input: "test_adversarial_keys", cfg: &Config{OmitEmpty: true, TagQuote: "double"}},
+		{name: "test_array_depth"},
+		{name: "test_array_depth_capped", input: "test_array_depth", cfg: &Config{OmitEmpty: true, ArrayDepth: 2}},
+		{name: "test_field_order_type_grouped", input: "test_field_order", cfg: &Config{OmitEmpty: true, FieldOrder: "type-grouped"}},
+		{name: "test_canonical", input: "test_field_order", cfg: &Config{OmitEmpty: true, Canonical: true, FieldOrder: "type-grouped", NoTagAlign: true, TagQuote: "double", ExampleComments: true}},
+		{name: "test_gen_marshalers", input: "test_field_order", cfg: &Config{OmitEmpty: true, GenMarshalers: true}},
+		{name: "test_gen_reset", input: "test_nested_json", cfg: &Config{OmitEmpty: true, NamedNested: true, GenReset: true}},
+		{name: "test_body_only", input: "test_detect_ip", cfg: &Config{OmitEmpty: true, DetectIP: true, BodyOnly: true}},
+		{name: "test_anonymous", input: "test_nested_json", cfg: &Config{OmitEmpty: true, Anonymous: true}},
+		{name: "test_anonymous_enums", input: "test_enums", cfg: &Config{Anonymous: true, Enums: 5}, wantErr: true},
+		{name: "test_raw_fields", cfg: &Config{OmitEmpty: true, RawFields: []string{"metadata", "payload"}, RawOnConflict: true}},
+		{name: "test_zod", input: "test_merge_report", cfg: &Config{OmitEmpty: true, Lang: "zod"}, golden: ".ts"},
+		{name: "test_zod_polymorphic", input: "test_polymorphic", cfg: &Config{Lang: "zod", PolymorphicField: "type", RootAlias: true}, golden: ".ts"},
+		{name: "test_json_schema", input: "test_merge_report", cfg: &Config{IntInference: true, Lang: "jsonschema"}, golden: ".schema.json"},
+		{name: "test_json_schema_polymorphic", input: "test_polymorphic", cfg: &Config{Lang: "jsonschema", PolymorphicField: "type", RootAlias: true}, golden: ".schema.json"},
+		{name: "test_proto", input: "test_polymorphic", cfg: &Config{IntInference: true, Lang: "proto"}, golden: ".proto"},
+		{name: "test_proto_maps", input: "test_detect_maps", cfg: &Config{DetectMaps: 2, Lang: "proto"}, golden: ".proto"},
+		{name: "test_format_query", ext: ".txt", cfg: &Config{OmitEmpty: true, Format: "query"}},
+		{name: "test_format_form", ext: ".txt", cfg: &Config{OmitEmpty: true, Format: "form"}},
+		{name: "test_json_strings", cfg: &Config{OmitEmpty: true, DetectJSONStrings: true}},
+		{name: "test_no_tag_align", input: "test_adversarial_keys", cfg: &Config{OmitEmpty: true, NoTagAlign: true}},
+		{name: "test_empty_object_as_map", cfg: &Config{OmitEmpty: true, EmptyObjectAsMap: true}},
+		{name: "test_json_number_fields", cfg: &Config{OmitEmpty: true, JSONNumberFields: []string{"id", "amounts"}}},
+		{name: "test_detect_constants", cfg: &Config{OmitEmpty: true, DetectConstants: true}},
+		{name: "test_emit_constants", input: "test_detect_constants", cfg: &Config{OmitEmpty: true, EmitConstants: true}},
+		{name: "test_field_order_first_record", cfg: &Config{OmitEmpty: true, FieldOrder: "first-record"}},
+		{name: "test_jsonpath_first_record", cfg: &Config{OmitEmpty: true, IntInference: true, FieldOrder: "first-record", JSONPath: "$.data.results[*]"}},
+		{name: "test_format_yaml_first_record", input: "test_format_yaml", ext: ".yaml", cfg: &Config{OmitEmpty: true, IntInference: true, Format: "yaml", FieldOrder: "first-record"}},
+		{name: "test_format_query_first_record", input: "test_format_query", ext: ".txt", cfg: &Config{OmitEmpty: true, Format: "query", FieldOrder: "first-record"}},
+		{name: "test_field_order_common_first", input: "test_merge_report", cfg: &Config{OmitEmpty: true, IntInference: true, FieldOrder: "common-first-alpha"}},
+		{name: "test_nullable_generic", input: "test_merge_report", cfg: &Config{OmitEmpty: true, Nullable: "generic"}},
+		{name: "test_jsonpath", cfg: &Config{OmitEmpty: true, JSONPath: "$.data['results'][*]"}},
+		{name: "test_jsonpath_no_match", input: "test_jsonpath", cfg: &Config{JSONPath: "$.data.missing[*]"}, wantErr: true},
+		{name: "test_jsonpath_not_object", input: "test_jsonpath", cfg: &Config{JSONPath: "$.data.total"}, wantErr: true},
+		{name: "test_scalar_array"},
+		{name: "test_scalar_array_widen", input: "test_scalar_array", cfg: &Config{OmitEmpty: true, ScalarArray: "widen"}},
+		{name: "test_no_ndjson", input: "test_ndjson", cfg: &Config{NoNDJSON: true}, wantErr: true},
+		// a fractional value anywhere must keep a field float64.
+		{name: "test_mixed_int_float"},
+		{name: "test_detect_uuid", cfg: &Config{OmitEmpty: true, DetectUUID: true}},
+		{name: "test_detect_uuid_type", input: "test_detect_uuid", cfg: &Config{OmitEmpty: true, DetectUUID: true, UUIDType: "github.com/gofrs/uuid/v5.UUID"}},
+		{name: "test_detect_uuid_null", cfg: &Config{OmitEmpty: true, DetectUUID: true, PointersForOptional: true}},
+		{name: "test_detect_uuid_nullable", input: "test_detect_uuid_null", cfg: &Config{OmitEmpty: true, DetectUUID: true, Nullable: "generic"}},
+		{name: "test_detect_uuid_type_invalid", input: "test_detect_uuid", cfg: &Config{DetectUUID: true, UUIDType: "UUID"}, wantErr: true},
+		{name: "test_sample_limit", input: "test_merge_report", cfg: &Config{OmitEmpty: true, IntInference: true, SampleLimit: 2}},
+		{name: "test_sample_limit_array", input: "test_mixed_int_float", cfg: &Config{OmitEmpty: true, IntInference: true, SampleLimit: 1}},
+		{name: "test_sample_limit_pretty", cfg: &Config{OmitEmpty: true, IntInference: true, SampleLimit: 1}},
+		{name: "test_null_heavy", cfg: &Config{OmitEmpty: true, NullHeavy: 0.5}},
+		{name: "test_int_type", input: "test_mixed_int_float", cfg: &Config{OmitEmpty: true, IntInference: true, IntType: "int"}},
+		{name: "test_gen_registry", input: "test_polymorphic", cfg: &Config{OmitEmpty: true, PolymorphicField: "type", RootAlias: true, GenRegistry: true}},
+		{name: "test_gen_registry_named", input: "test_named_nested", cfg: &Config{OmitEmpty: true, IntInference: true, NamedNested: true, GenRegistry: true}},
+		{name: "test_layout_leaf_first", input: "test_polymorphic", cfg: &Config{OmitEmpty: true, PolymorphicField: "type", Layout: "leaf-first"}},
+		{name: "test_layout_leaf_first_alias", input: "test_repeated_json", cfg: &Config{OmitEmpty: true, RootAlias: true, GenConstructor: true, Layout: "leaf-first"}},
+		{name: "test_pointers_for_optional", input: "test_merge_report", cfg: &Config{OmitEmpty: true, IntInference: true, PointersForOptional: true}},
+		{name: "test_nullable_mixed_numbers", cfg: &Config{OmitEmpty: true, IntInference: true, PointersForOptional: true}},
+		{name: "test_nullable_mixed_numbers_generic", input: "test_nullable_mixed_numbers", cfg: &Config{OmitEmpty: true, IntInference: true, Nullable: "generic"}},
+		{name: "test_require_all_present", input: "test_merge_report", cfg: &Config{RequireAllPresent: true, Optional: []string{"name"}}, wantErr: true},
+		{name: "test_require_all_present_null_parent", input: "test_null_parent", cfg: &Config{OmitEmpty: true, IntInference: true, RequireAllPresent: true, Optional: []string{"o"}}},
+		{name: "test_pointers_null_parent", input: "test_null_parent", cfg: &Config{OmitEmpty: true, IntInference: true, PointersForOptional: true, SmartOmitEmpty: true, RareDeprecated: 0.6}},
+		{name: "test_nullable_null_parent", input: "test_null_parent_missing", cfg: &Config{OmitEmpty: true, IntInference: true, Nullable: "generic"}},
+		{name: "test_require_all_present_allowed", input: "test_merge_report", cfg: &Config{OmitEmpty: true, RequireAllPresent: true, Optional: []string{"name", "owner", "admin"}}},
+		{name: "test_use_json_number", input: "test_json_number_fields", cfg: &Config{OmitEmpty: true, UseJSONNumber: true}},
+		{name: "test_embed_sample", input: "test_simple_json", cfg: &Config{OmitEmpty: true, EmbedSample: "testdata/sample.json"}},
+		{name: "test_embed_sample_invalid", input: "test_simple_json", cfg: &Config{EmbedSample: "../sample.json"}, wantErr: true},
+		{name: "test_tags_json_yaml", input: "test_tag_case", cfg: &Config{OmitEmpty: true, Tags: []string{"json", "yaml"}}},
+		{name: "test_tags_yaml", input: "test_tag_case", cfg: &Config{OmitEmpty: true, Tags: []string{"yaml"}}},
+		{name: "test_tags_bson", cfg: &Config{OmitEmpty: true, IntInference: true, Tags: []string{"json", "bson"}}},
+		{name: "test_known_types", input: "test_merge_report", cfg: &Config{OmitEmpty: true, KnownTypes: map[string][]KnownField{"Account": {{Key: "Login", Type: "string"}, {Key: "admin", Type: "bool"}}}}},
+		{name: "test_known_types_mismatch", input: "test_merge_report", cfg: &Config{OmitEmpty: true, KnownTypes: map[string][]KnownField{"Account": {{Key: "Login", Type: "int64"}, {Key: "admin", Type: "bool"}}}}},
+		{name: "test_format_yaml", ext: ".yaml", cfg: &Config{OmitEmpty: true, IntInference: true, Format: "yaml"}},
+		{name: "test_named_nested", cfg: &Config{OmitEmpty: true, IntInference: true, NamedNested: true}},
+		{name: "test_named_nested_collision", cfg: &Config{OmitEmpty: true, IntInference: true, NamedNested: true, RootAlias: true, Enums: 3}},
+		{name: "test_named_nested_generic", cfg: &Config{OmitEmpty: true, IntInference: true, NamedNested: true}},
+		{name: "test_named_nested_numbered", cfg: &Config{OmitEmpty: true, IntInference: true, NamedNested: true}},
+		{name: "test_heterogeneous_array", cfg: &Config{OmitEmpty: true, IntInference: true, PointersForOptional: true}},
+		{name: "test_array_union", cfg: &Config{OmitEmpty: true, IntInference: true, PointersForOptional: true}},
+		{name: "test_smart_omitempty", input: "test_heterogeneous_array", cfg: &Config{OmitEmpty: true, IntInference: true, SmartOmitEmpty: true}},
+		{name: "test_honest_omitempty", input: "test_merge_report", cfg: &Config{OmitEmpty: true, IntInference: true, PointersForOptional: true, HonestOmitEmpty: true}},
+		{name: "test_flexible_types", cfg: &Config{OmitEmpty: true, IntInference: true, FlexibleTypes: true}},
+		{name: "test_unwrap_singletons", cfg: &Config{OmitEmpty: true, IntInference: true, UnwrapSingletons: true}},
+		{name: "test_max_depth", cfg: &Config{OmitEmpty: true, IntInference: true, MaxDepth: 1}},
+		{name: "test_detect_maps", cfg: &Config{OmitEmpty: true, IntInference: true, DetectMaps: 3, PointersForOptional: true}},
+		{name: "test_root_scalar", cfg: &Config{OmitEmpty: true, IntInference: true}},
+		{name: "test_nullable_arrays", cfg: &Config{OmitEmpty: true, IntInference: true, PointersForOptional: true}},
+		{name: "test_struct_comment", input: "test_simple_json", cfg: &Config{OmitEmpty: true, StructComment: "test_struct_comment is a user.\nIt is generated."}},
+		{name: "test_field_types", cfg: &Config{OmitEmpty: true, IntInference: true, FieldTypes: map[string]string{"id": "uint64", "price": "github.com/shopspring/decimal.Decimal", "at": "*time.Duration", "missing": "int"}}},
+		{name: "test_detect_bytes", cfg: &Config{OmitEmpty: true, DetectBytes: true}},
+		{name: "test_detect_bytes_null", cfg: &Config{OmitEmpty: true, DetectBytes: true, PointersForOptional: true}},
+		{name: "test_detect_bytes_null_schema", input: "test_detect_bytes_null", cfg: &Config{DetectBytes: true, PointersForOptional: true, Lang: "jsonschema"}, golden: ".schema.json"},
+		{name: "test_ndjson_arrays", cfg: &Config{OmitEmpty: true, IntInference: true, PointersForOptional: true}},
+		{name: "test_name_case_preserve", input: "test_tag_case", cfg: &Config{OmitEmpty: true, IntInference: true, NameCase: "preserve"}},
+		{name: "test_name_case_screaming_snake", input: "test_tag_case", cfg: &Config{OmitEmpty: true, IntInference: true, NameCase: "screaming-snake"}},
+		{name: "test_name_case_exported", cfg: &Config{OmitEmpty: true, IntInference: true, NameCase: "preserve"}},
+		{name: "test_name_case_invalid", input: "test_tag_case", cfg: &Config{NameCase: "kebab"}, wantErr: true},
+		{name: "test_example_comments", input: "more_complex_example", cfg: &Config{OmitEmpty: true, IntInference: true, ExampleComments: true}},
+		{name: "test_ragged_arrays", cfg: &Config{OmitEmpty: true, IntInference: true}},
+		{name: "test_enums", cfg: &Config{OmitEmpty: true, IntInference: true, Enums: 4}},
+		{name: "test_enums_int", cfg: &Config{OmitEmpty: true, IntInference: true, Enums: 4}},
+		{name: "test_enums_nullable", cfg: &Config{OmitEmpty: true, IntInference: true, Enums: 4, Nullable: "generic"}},
+		{name: "test_tag_case_invalid", input: "test_tag_case", cfg: &Config{TagCase: "shouty"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		tt := tt // capture range variable
+		t.Run(tt.name, func(t *testing.T) {
+			//t.Parallel()
+			inputName := tt.input
+			if inputName == "" {
+				inputName = tt.name
+			}
+			ext := tt.ext
+			if ext == "" {
+				ext = ".json"
+			}
+			input := openTestData(t, inputName+ext)
+			got, err := generate(bytes.NewReader(input), tt.name, "test_package", tt.cfg)
+			if err != nil {
+				if tt.wantErr {
+					t.Logf("generate() got expected error = %v", err)
+					return
+				}
+				t.Errorf("generate() error = %v, wantErr %v", err, tt.wantErr)
+				t.Errorf("generate() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			goldenFile := tt.name + ".go"
+			if tt.golden != "" {
+				goldenFile = tt.name + tt.golden
+			}
+			if writeGolden {
+				t.Log("writing golden file")
+				writeTestData(t, goldenFile, got)
+				return
+			}
+			want := string(openTestData(t, goldenFile))
+			gotStr := string(got)
+			if diff := cmp.Diff(want, gotStr); diff != "" {
+				t.Errorf("generate() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestMergeReport(t *testing.T) {
+	var report bytes.Buffer
+	cfg := DefaultConfig
+	cfg.MergeReport = &report
+	input := openTestData(t, "test_merge_report.json")
+	if _, err := generate(bytes.NewReader(input), "Foo", "test_package", &cfg); err != nil {
+		t.Fatal(err)
+	}
+	if writeGolden {
+		writeTestData(t, "test_merge_report.txt", report.Bytes())
+		return
+	}
+	want := string(openTestData(t, "test_merge_report.txt"))
+	if diff := cmp.Diff(want, report.String()); diff != "" {
+		t.Errorf("merge report mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestHistogram(t *testing.T) {
+	var histogram bytes.Buffer
+	cfg := DefaultConfig
+	cfg.Histogram = &histogram
+	input := openTestData(t, "test_histogram.json")
+	if _, err := generate(bytes.NewReader(input), "Foo", "test_package", &cfg); err != nil {
+		t.Fatal(err)
+	}
+	if writeGolden {
+		writeTestData(t, "test_histogram.txt", histogram.Bytes())
+		return
+	}
+	want := string(openTestData(t, "test_histogram.txt"))
+	if diff := cmp.Diff(want, histogram.String()); diff != "" {
+		t.Errorf("histogram mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestMergeNumbersSample(t *testing.T) {
+	cfg := &Config{}
+	typ := &Type{Config: cfg}
+	for i := 0; i < 3*maxNumbers; i++ {
+		typ.Merge(&Type{Config: cfg, Numbers: []float64{float64(i)}, NumberCount: 1})
+	}
+	if typ.NumberCount != 3*maxNumbers {
+		t.Errorf("NumberCount = %d, want %d", typ.NumberCount, 3*maxNumbers)
+	}
+	if len(typ.Numbers) != maxNumbers {
+		t.Fatalf("len(Numbers) = %d, want %d", len(typ.Numbers), maxNumbers)
+	}
+	// a uniform sample of 0 to 3*maxNumbers has about a third of its values
+	// past 2*maxNumbers.
+	late := 0
+	for _, v := range typ.Numbers {
+		if v >= 2*maxNumbers {
+			late++
+		}
+	}
+	if late < maxNumbers/4 || late > maxNumbers/2 {
+		t.Errorf("%d of %d sampled values are from the last third, want about a third", late, maxNumbers)
+	}
+}
+
+func TestPercentilesSample(t *testing.T) {
+	const n, sample = 100000, 2000
+	cfg := &Config{NumberSample: sample}
+	typ := &Type{Config: cfg}
+	exact := make([]float64, 0, n)
+	for i := 0; i < n; i++ {
+		// a skewed distribution, visited out of order.
+		v := math.Pow(float64(i*7919%n), 2) / n
+		exact = append(exact, v)
+		typ.Merge(&Type{Config: cfg, Numbers: []float64{v}, NumberCount: 1})
+	}
+	if len(typ.Numbers) != sample {
+		t.Fatalf("len(Numbers) = %d, want %d", len(typ.Numbers), sample)
+	}
+	sort.Float64s(exact)
+	for _, p := range []float64{10, 50, 90, 99} {
+		got := percentiles(typ.Numbers, p)[0]
+		// the fraction of all values below the sampled percentile should be
+		// within 2.5 points of the exact one.
+		rank := 100 * float64(sort.SearchFloat64s(exact, got)) / n
+		if math.Abs(rank-p) > 2.5 {
+			t.Errorf("sampled p%g = %g, the exact p%.3g", p, got, rank)
+		}
+	}
+}
+
+func TestGetComment(t *testing.T) {
+	tests := []struct {
+		comment string
+		want    string
+	}{
+		{comment: "", want: ""},
+		{comment: "plain", want: "// plain\n"},
+		{comment: "two\nlines", want: "// two\n// lines\n"},
+		{comment: "ends */ a block", want: "// ends * / a block\n"},
+		{comment: "carriage\r return\x00 and\ttab", want: "// carriage return and tab\n"},
+		{comment: "`backticks` are fine", want: "// `backticks` are fine\n"},
+	}
+	for _, tt := range tests {
+		typ := &Type{Comment: tt.comment}
+		if got := typ.GetComment(); got != tt.want {
+			t.Errorf("GetComment() for %q = %q, want %q", tt.comment, got, tt.want)
+		}
+	}
+}
+
+func TestSourceMap(t *testing.T) {
+	var sourceMap bytes.Buffer
+	cfg := DefaultConfig
+	cfg.PolymorphicField = "type"
+	cfg.SourceMap = &sourceMap
+	input := openTestData(t, "test_polymorphic.json")
+	if _, err := generate(bytes.NewReader(input), "Foo", "test_package", &cfg); err != nil {
+		t.Fatal(err)
+	}
+	if writeGolden {
+		writeTestData(t, "test_source_map.json", sourceMap.Bytes())
+		return
+	}
+	want := string(openTestData(t, "test_source_map.json"))
+	if diff := cmp.Diff(want, sourceMap.String()); diff != "" {
+		t.Errorf("source map mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestCanonicalStable(t *testing.T) {
+	cfg := DefaultConfig
+	cfg.Canonical = true
+	cfg.FieldOrder = "type-grouped"
+	cfg.CanonicalizeKeys = true
+	cfg.PolymorphicField = "type"
+	input := openTestData(t, "test_polymorphic.json")
+	want, err := generate(bytes.NewReader(input), "Foo", "test_package", &cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 100; i++ {
+		got, err := generate(bytes.NewReader(input), "Foo", "test_package", &cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(string(want), string(got)); diff != "" {
+			t.Fatalf("run %d differs (-want +got):\n%s", i, diff)
+		}
+	}
+}
+
+func TestGenExample(t *testing.T) {
+	var example bytes.Buffer
+	cfg := DefaultConfig
+	cfg.Example = &example
+	input := openTestData(t, "test_merge_report.json")
+	if _, err := generate(bytes.NewReader(input), "Foo", "test_package", &cfg); err != nil {
+		t.Fatal(err)
+	}
+	if writeGolden {
+		writeTestData(t, "test_gen_example.json", example.Bytes())
+		return
+	}
+	want := string(openTestData(t, "test_gen_example.json"))
+	if diff := cmp.Diff(want, example.String()); diff != "" {
+		t.Errorf("example mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestLoadKnownTypes(t *testing.T) {
+	got, err := LoadKnownTypes("testdata/known")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string][]KnownField{
+		"Address": {{Key: "street", Type: "string"}, {Key: "city", Type: "string"}, {Key: "Zip", Type: "string"}},
+		"Money":   {{Key: "amount", Type: "int64"}, {Key: "currency", Type: "string"}},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("LoadKnownTypes() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestFieldOrderStable(t *testing.T) {
+	input := openTestData(t, "test_merge_report.json")
+	for _, order := range []string{"alphabetical", "type-grouped", "first-record", "common-first-alpha"} {
+		cfg := DefaultConfig
+		cfg.FieldOrder = order
+		want, err := generate(bytes.NewReader(input), "Foo", "test_package", &cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i := 0; i < 100; i++ {
+			got, err := generate(bytes.NewReader(input), "Foo", "test_package", &cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(string(want), string(got)); diff != "" {
+				t.Fatalf("%s: run %d differs (-want +got):\n%s", order, i, diff)
+			}
+		}
+	}
+}
+
+func TestCheckDrift(t *testing.T) {
+	input := openTestData(t, "test_merge_report.json")
+	var buf bytes.Buffer
+	opts := Options{Name: "User", Config: DefaultConfig}
+	n, err := CheckDrift(&buf, bytes.NewReader(input), "testdata/test_drift.go", opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `User.Score: declared float64, seen as float64 (1), int64 (2), string (1)
+User.Removed: key "removed" never seen
+User.Owner.Admin: declared int, seen as bool
+User.Owner: key "login" not in struct, seen as string
+User: 5 of 6 fields seen, 1 unknown keys, 2 type mismatches
+`
+	if diff := cmp.Diff(want, buf.String()); diff != "" {
+		t.Errorf("CheckDrift() mismatch (-want +got):\n%s", diff)
+	}
+	if n != 4 {
+		t.Errorf("CheckDrift() = %d, want 4", n)
+	}
+}
+
+// failingReader fails every read, standing in for input that mustn't be read.
+type failingReader struct{}
+
+func (failingReader) Read([]byte) (int, error) {
+	return 0, fmt.Errorf("read past the sample limit")
+}
+
+func TestSampleLimitStopsReading(t *testing.T) {
+	for _, input := range []string{"{\"a\":1}\n{\"b\":2}\n", "[{\"a\":1},{\"b\":2},"} {
+		cfg := DefaultConfig
+		cfg.SampleLimit = 2
+		r := io.MultiReader(strings.NewReader(input), failingReader{})
+		if _, err := generate(r, "Foo", "test_package", &cfg); err != nil {
+			t.Errorf("generate(%q) error = %v", input, err)
+		}
+	}
+}
+
+func TestDecodeNDJSONTrailingData(t *testing.T) {
+	var bad bytes.Buffer
+	cfg := DefaultConfig
+	cfg.BadLines = &bad
+	got, err := decodeNDJSON([]byte("{\"a\":1}\n{\"a\":2}]\n{\"a\":3} {\"a\":4}\n"), &cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 3 {
+		t.Errorf("decodeNDJSON() = %d records, want 3", len(got))
+	}
+	if want := "{\"a\":2}]\n"; bad.String() != want {
+		t.Errorf("bad lines = %q, want %q", bad.String(), want)
+	}
+}
+
+// TestDecodeStreams checks that values concatenated on one line, and lines
+// of scalars, are each decoded as records.
+func TestDecodeStreams(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{`{"a":1}{"a":2.5}`, "A float64 "},
+		{"{\"a\":1} {\"b\":\"x\"}\n", "B string "},
+		{"1\n2\n", "type Foo int64"},
+		{"\"x\"\n\"y\"\n", "type Foo string"},
+	}
+	for _, tt := range tests {
+		cfg := DefaultConfig
+		got, err := generate(strings.NewReader(tt.input), "Foo", "test_package", &cfg)
+		if err != nil {
+			t.Errorf("generate(%q) error = %v", tt.input, err)
+			continue
+		}
+		if !strings.Contains(string(got), tt.want) {
+			t.Errorf("generate(%q) = \n%s\nwant %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestJSONPathNumber(t *testing.T) {
+	cfg := DefaultConfig
+	cfg.UseJSONNumber = true
+	cfg.JSONPath = "$.data.total"
+	_, err := generate(strings.NewReader(string(openTestData(t, "test_jsonpath.json"))), "Foo", "test_package", &cfg)
+	if err == nil || !strings.Contains(err.Error(), "matched a number") {
+		t.Errorf("generate() error = %v, want matched a number", err)
+	}
+}
+
+func TestRecords(t *testing.T) {
+	var gzipped bytes.Buffer
+	zw := gzip.NewWriter(&gzipped)
+	zw.Write(openTestData(t, "test_ndjson.json"))
+	zw.Close()
+	tests := []struct {
+		name  string
+		input []byte
+		cfg   Config
+		want  int
+	}{
+		{"ndjson", openTestData(t, "test_ndjson.json"), Config{}, 3},
+		{"gzip", gzipped.Bytes(), Config{}, 3},
+		{"yaml", openTestData(t, "test_format_yaml.yaml"), Config{Format: "yaml"}, 2},
+		{"query", openTestData(t, "test_format_query.txt"), Config{Format: "query"}, 3},
+		{"jsonpath", openTestData(t, "test_jsonpath.json"), Config{JSONPath: "$.data.results[*]"}, 2},
+		{"single", openTestData(t, "test_simple_json.json"), Config{}, 1},
+	}
+	for _, tt := range tests {
+		var got int
+		cfg := tt.cfg
+		cfg.Records = &got
+		if _, err := generate(bytes.NewReader(tt.input), "Foo", "test_package", &cfg); err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%s: Records = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestOpenFiles(t *testing.T) {
+	files, err := OpenFiles("testdata/test_sample_limit_pretty.json", "testdata/test_tag_case.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer files.Close()
+	cfg := DefaultConfig
+	cfg.NoNDJSON = true
+	got, err := generate(files, "Foo", "test_package", &cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"Owner ", "FirstName ", "HTTPStatus "} {
+		if !strings.Contains(string(got), want) {
+			t.Errorf("generate() missing field %q:\n%s", want, got)
+		}
+	}
+}
+
+func TestParallel(t *testing.T) {
+	var input strings.Builder
+	for i := 0; i < 3000; i++ {
+		switch i % 4 {
+		case 0:
+			fmt.Fprintf(&input, "{\"id\":%d,\"name\":\"user%d\",\"tags\":[\"a\"]}\n", i, i%7)
+		case 1:
+			fmt.Fprintf(&input, "{\"id\":%d,\"name\":null,\"owner\":{\"login\":\"x\"}}\n", i)
+		case 2:
+			fmt.Fprintf(&input, "{\"id\":%d.5,\"score\":\"n/a\",\"owner\":{\"admin\":true}}\n", i)
+		case 3:
+			fmt.Fprintf(&input, "{\"id\":%d,\"tags\":[],\"score\":%d}\n", i, i)
+		}
+	}
+	for _, base := range []Config{DefaultConfig, {OmitEmpty: true, IntInference: true, Enums: 10, PointersForOptional: true}} {
+		serial, parallel := base, base
+		parallel.Parallel = 4
+		want, err := generate(strings.NewReader(input.String()), "Foo", "test_package", &serial)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := generate(strings.NewReader(input.String()), "Foo", "test_package", &parallel)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(string(want), string(got)); diff != "" {
+			t.Errorf("parallel output differs (-serial +parallel):\n%s", diff)
+		}
+	}
+}
+
+func TestSingularize(t *testing.T) {
+	tests := map[string]string{
+		"Users":      "User",
+		"Categories": "Category",
+		"Boxes":      "Box",
+		"Matches":    "Match",
+		"Addresses":  "Address",
+		"People":     "Person",
+		"Metadata":   "Metadata",
+		"Status":     "Status",
+		"Address":    "Address",
+		"UserIDs":    "UserID",
+	}
+	for plural, want := range tests {
+		if got := singularize(plural); got != want {
+			t.Errorf("singularize(%q) = %q, want %q", plural, got, want)
+		}
+	}
+}
+
+func TestCollectImports(t *testing.T) {
+	types := []*Type{{Type: "struct", Children: Fields{
+		{Type: "time.Time"},
+		{Type: "json.Number"},
+		{Type: "struct", Children: Fields{{Type: "time.Time"}, {Type: "net.IP"}}},
+	}}}
+	got := collectImports(types, "github.com/shopspring/decimal", "encoding/json", "embed")
+	want := []string{"embed", "encoding/json", "github.com/shopspring/decimal", "net", "time"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("collectImports() mismatch (-want +got):\n%s", diff)
+	}
+	wantBlock := "import (\n_ \"embed\"\n\"encoding/json\"\n\"net\"\n\"time\"\n\n\"github.com/shopspring/decimal\"\n)\n"
+	if block := importBlock(got); block != wantBlock {
+		t.Errorf("importBlock() = %q, want %q", block, wantBlock)
+	}
+}
+
+func TestFmtFieldName(t *testing.T) {
+	tests := []struct {
+		key   string
+		extra []string
+		want  string
+	}{
+		{"foo_id", nil, "FooID"},
+		{"html_url", nil, "HTMLURL"},
+		{"http_api_id", nil, "HTTPAPIID"},
+		{"api_version", nil, "APIVersion"},
+		{"idle", nil, "Idle"},
+		{"k8s_cluster", nil, "K8sCluster"},
+		{"k8s_cluster", []string{"K8S"}, "K8SCluster"},
+	}
+	for _, tt := range tests {
+		if got := fmtFieldName(tt.key, tt.extra); got != tt.want {
+			t.Errorf("fmtFieldName(%q, %q) = %q, want %q", tt.key, tt.extra, got, tt.want)
+		}
+	}
+}
+
+// TestDetectIPRoundTrip checks that the struct emitted by -detect-ip
+// round-trips IPv4 and IPv6 addresses through encoding/json. The struct is
+// rebuilt with reflect from the fields of the generated source.
+func TestDetectIPRoundTrip(t *testing.T) {
+	input := `{"client":"10.0.0.1","server":"2001:db8::1"}`
+	cfg := DefaultConfig
+	cfg.DetectIP = true
+	cfg.OmitEmpty = false
+	src, err := generate(strings.NewReader(input), "Foo", "test_package", &cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	file, err := parser.ParseFile(token.NewFileSet(), "", src, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	goTypes := map[string]reflect.Type{"net.IP": reflect.TypeOf(net.IP{}), "string": reflect.TypeOf("")}
+	var fields []reflect.StructField
+	ast.Inspect(file, func(n ast.Node) bool {
+		st, ok := n.(*ast.StructType)
+		if !ok {
+			return true
+		}
+		for _, field := range st.Fields.List {
+			typ := types.ExprString(field.Type)
+			if goTypes[typ] == nil {
+				t.Fatalf("generated field %s has type %s", field.Names[0].Name, typ)
+			}
+			tag, _ := strconv.Unquote(field.Tag.Value)
+			fields = append(fields, reflect.StructField{Name: field.Names[0].Name, Type: goTypes[typ], Tag: reflect.StructTag(tag)})
+		}
+		return false
+	})
+	if len(fields) != 2 || fields[0].Type != goTypes["net.IP"] || fields[1].Type != goTypes["net.IP"] {
+		t.Fatalf("generated fields %v, want two net.IP fields:\n%s", fields, src)
+	}
+	v := reflect.New(reflect.StructOf(fields)).Interface()
+	if err := json.Unmarshal([]byte(input), v); err != nil {
+		t.Fatal(err)
+	}
+	got, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(input, string(got)); diff != "" {
+		t.Errorf("round trip mismatch (-want +got):\n%s", diff)
+	}
+}
+
+// TestMixedNumberOrder checks that integers and floats merge to float64
+// whatever order they are seen in, and that integral floats stay integers.
+func TestMixedNumberOrder(t *testing.T) {
+	tests := []struct {
+		lines []string
+		want  string
+	}{
+		{[]string{"1", "2"}, "int64"},
+		{[]string{"1", "1.5"}, "float64"},
+		{[]string{"1.5", "1"}, "float64"},
+		{[]string{"1", "1.5", "2"}, "float64"},
+		{[]string{"1.5", "1", "2"}, "float64"},
+		{[]string{"1", "2", "1.5"}, "float64"},
+		{[]string{"1.0", "2"}, "int64"},
+		{[]string{"[1]", "[1.5]"}, "[]float64"},
+	}
+	for _, tt := range tests {
+		var input strings.Builder
+		for _, line := range tt.lines {
+			fmt.Fprintf(&input, "{\"n\":%s}\n", line)
+		}
+		cfg := DefaultConfig
+		got, err := generate(strings.NewReader(input.String()), "Foo", "test_package", &cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(string(got), "N "+tt.want+" ") {
+			t.Errorf("%v: got\n%s\nwant field of type %s", tt.lines, got, tt.want)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	cfg := DefaultConfig
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("DefaultConfig.Validate() = %v, want nil", err)
+	}
+	cfg.FieldOrder = "alphabetic"
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "unknown field order") {
+		t.Errorf("Validate() with FieldOrder %q = %v, want unknown field order error", cfg.FieldOrder, err)
+	}
+	if _, err := NewGenerator(Options{Name: "Foo", Package: "test_package", Config: cfg}); err == nil {
+		t.Errorf("NewGenerator() with FieldOrder %q = nil error, want unknown field order error", cfg.FieldOrder)
+	}
+}
+
+func TestValidateJSONPath(t *testing.T) {
+	tests := map[string]string{
+		"$..id":            "recursive descent",
+		"$.items[?(@.id)]": "filter expressions",
+		"$.items[0:2]":     "array slices",
+		"$['a','b']":       "unions",
+		"$.data.results":   "",
+		"$['a:b']":         "",
+	}
+	for path, want := range tests {
+		cfg := DefaultConfig
+		cfg.JSONPath = path
+		err := cfg.Validate()
+		switch {
+		case want == "" && err != nil:
+			t.Errorf("Validate() with JSONPath %q = %v, want nil", path, err)
+		case want != "" && (err == nil || !strings.Contains(err.Error(), want)):
+			t.Errorf("Validate() with JSONPath %q = %v, want %s error", path, err, want)
+		}
+	}
+}
+
+func openTestData(t *testing.T, filename string) []byte {
+	input, err := ioutil.ReadFile("testdata/" + filename)
+	if err != nil {
+		t.Error(err)
+	}
+	return input
+}
+
+func writeTestData(t *testing.T, filename string, contents []byte) {
+	err := ioutil.WriteFile("testdata/"+filename, contents, 0644)
+	if err != nil {
+		t.Error(err)
+	}
+}
+func trimStringSlice(strs []string) []string {
+	var result []string
+	for _, s := range strs {
+		n := strings.TrimSpace(s)
+		result = append(result, n)
+	}
+	return result
+}
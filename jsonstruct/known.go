@@ -0,0 +1,197 @@
+package jsonstruct
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// KnownField is a field of a known struct type.
+type KnownField struct {
+	// Key is the JSON key the field is encoded as.
+	Key string
+	// Type is the Go type of the field, such as "string" or "[]Address".
+	Type string
+}
+
+// LoadKnownTypes parses the Go file, or the Go files of the directory, at
+// path and returns the fields of each struct type it declares, keyed by type
+// name.
+func LoadKnownTypes(path string) (map[string][]KnownField, error) {
+	structs, err := loadStructs(path)
+	if err != nil {
+		return nil, err
+	}
+	known := map[string][]KnownField{}
+	for name, st := range structs {
+		var fields []KnownField
+		for _, field := range structFields(st) {
+			fields = append(fields, KnownField{Key: field.key, Type: types.ExprString(field.typ)})
+		}
+		known[name] = fields
+	}
+	return known, nil
+}
+
+// loadStructs parses the Go file, or the Go files of the directory, at path
+// and returns the struct types it declares, keyed by type name.
+func loadStructs(path string) (map[string]*ast.StructType, error) {
+	files := []string{path}
+	if info, err := os.Stat(path); err != nil {
+		return nil, err
+	} else if info.IsDir() {
+		if files, err = filepath.Glob(filepath.Join(path, "*.go")); err != nil {
+			return nil, err
+		}
+	}
+	structs := map[string]*ast.StructType{}
+	fset := token.NewFileSet()
+	for _, file := range files {
+		f, err := parser.ParseFile(fset, file, nil, 0)
+		if err != nil {
+			return nil, err
+		}
+		for _, decl := range f.Decls {
+			d, ok := decl.(*ast.GenDecl)
+			if !ok || d.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range d.Specs {
+				ts := spec.(*ast.TypeSpec)
+				if st, ok := ts.Type.(*ast.StructType); ok {
+					structs[ts.Name.Name] = st
+				}
+			}
+		}
+	}
+	return structs, nil
+}
+
+// structField is a field of a declared struct type.
+type structField struct {
+	// key is the JSON key the field is encoded as.
+	key  string
+	name string
+	typ  ast.Expr
+}
+
+// structFields returns the fields of st that encoding/json encodes, with the
+// keys it encodes them as. Embedded fields are skipped.
+func structFields(st *ast.StructType) []structField {
+	var fields []structField
+	for _, field := range st.Fields.List {
+		var tag reflect.StructTag
+		if field.Tag != nil {
+			if s, err := strconv.Unquote(field.Tag.Value); err == nil {
+				tag = reflect.StructTag(s)
+			}
+		}
+		name := strings.Split(tag.Get("json"), ",")[0]
+		if name == "-" {
+			continue
+		}
+		for _, ident := range field.Names {
+			if !ident.IsExported() {
+				continue
+			}
+			key := name
+			if key == "" {
+				key = ident.Name
+			}
+			fields = append(fields, structField{key: key, name: ident.Name, typ: field.Type})
+		}
+	}
+	return fields
+}
+
+// matchKnownTypes replaces each nested struct of typ whose JSON keys are the
+// same as those of a known type, ignoring case as encoding/json does, and
+// whose values decode into the types of its fields, with a reference to that
+// type. Matches are reported to w if it is non-nil.
+func matchKnownTypes(typ *Type, path string, known map[string][]KnownField, w io.Writer) {
+	names := make([]string, 0, len(known))
+	signatures := map[string]string{}
+	for name, fields := range known {
+		names = append(names, name)
+		keys := make([]string, 0, len(fields))
+		for _, field := range fields {
+			keys = append(keys, field.Key)
+		}
+		signatures[name] = keySignature(keys)
+	}
+	sort.Strings(names)
+
+	var walk func(t *Type, path string)
+	walk = func(t *Type, path string) {
+		for _, field := range t.Children {
+			fieldPath := path + "." + field.Name
+			if field.Type == "struct" && len(field.Children) > 0 {
+				keys := make([]string, 0, len(field.Children))
+				for _, child := range field.Children {
+					keys = append(keys, child.Key)
+				}
+				signature := keySignature(keys)
+				matched := false
+				for _, name := range names {
+					if signatures[name] == signature && holdsFields(known[name], field) {
+						field.Type, field.Children = name, nil
+						if w != nil {
+							fmt.Fprintf(w, "%s: matched known type %s\n", fieldPath, name)
+						}
+						matched = true
+						break
+					}
+				}
+				if matched {
+					continue
+				}
+			}
+			walk(field, fieldPath)
+		}
+	}
+	walk(typ, path)
+}
+
+// holdsFields reports whether the values of each field of the struct type t
+// decode into the type of the known field with the same key, as CheckDrift
+// would check them.
+func holdsFields(fields []KnownField, t *Type) bool {
+	inferred := map[string]*Type{}
+	for _, child := range t.Children {
+		inferred[strings.ToLower(child.Key)] = child
+	}
+	c := &driftChecker{w: ioutil.Discard}
+	for _, field := range fields {
+		expr, err := parser.ParseExpr(field.Type)
+		if err != nil {
+			return false
+		}
+		child, ok := inferred[strings.ToLower(field.Key)]
+		if !ok {
+			return false
+		}
+		c.checkType(expr, child, "")
+	}
+	return c.mismatches == 0 && c.unknown == 0 && c.unseen == 0
+}
+
+// keySignature returns the sorted, lowercased keys joined into a string that
+// is equal for key sets encoding/json treats the same.
+func keySignature(keys []string) string {
+	lower := make([]string, len(keys))
+	for i, key := range keys {
+		lower[i] = strings.ToLower(key)
+	}
+	sort.Strings(lower)
+	return strings.Join(lower, "\x00")
+}
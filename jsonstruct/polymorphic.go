@@ -0,0 +1,151 @@
+package jsonstruct
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// generateVariants groups the objects in values by the string value of their
+// discriminator key and returns a merged type for each group, ordered by
+// discriminator value. It returns nil unless every value is an object with a
+// string discriminator.
+func generateVariants(values []interface{}, discriminator string, cfg *Config) ([]*Type, error) {
+	groups := map[string][]interface{}{}
+	for _, v := range values {
+		obj, ok := v.(map[string]interface{})
+		if !ok {
+			return nil, nil
+		}
+		d, ok := obj[discriminator].(string)
+		if !ok {
+			return nil, nil
+		}
+		groups[d] = append(groups[d], v)
+	}
+	keys := make([]string, 0, len(groups))
+	for key := range groups {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	variants := make([]*Type, 0, len(keys))
+	for _, key := range keys {
+		variant, err := generateMergedType("", groups[key], cfg)
+		if err != nil {
+			return nil, err
+		}
+		variant.Key = key
+		variants = append(variants, variant)
+	}
+	return variants, nil
+}
+
+// mergeVariants merges the variants of t2 into those of t, matching them by
+// discriminator value.
+func (t *Type) mergeVariants(t2 *Type) error {
+	if t.Variants == nil || t2.Variants == nil {
+		// only some of the values were polymorphic.
+		t.Variants = nil
+		return nil
+	}
+	byKey := map[string]*Type{}
+	for _, variant := range t.Variants {
+		byKey[variant.Key] = variant
+	}
+	for _, variant := range t2.Variants {
+		if v, ok := byKey[variant.Key]; ok {
+			if err := v.Merge(variant); err != nil {
+				return fmt.Errorf("issue with variant %q: %w", variant.Key, err)
+			}
+			continue
+		}
+		t.Variants = append(t.Variants, variant)
+	}
+	sort.Slice(t.Variants, func(i, j int) bool {
+		return t.Variants[i].Key < t.Variants[j].Key
+	})
+	return nil
+}
+
+// polymorphicDecls replaces each polymorphic array field of typ, and of its
+// nested structs, with a named slice type of an interface implemented by a
+// struct per discriminator value. It returns the declarations for those types
+// and the variant structs. Type names are made unique against used, and added
+// to it.
+//
+// The named slice type has an UnmarshalJSON method that decodes each element
+// into the variant selected by its discriminator, so the field can be
+// unmarshaled directly. Unknown discriminator values are reported as errors.
+func polymorphicDecls(typ *Type, path, discriminator string, used map[string]bool) ([]string, []*Type) {
+	var decls []string
+	var variants []*Type
+	for _, field := range typ.Children {
+		fieldPath := path + field.Name
+		if len(field.Variants) < 2 {
+			d, v := polymorphicDecls(field, fieldPath, discriminator, used)
+			decls, variants = append(decls, d...), append(variants, v...)
+			continue
+		}
+		sliceName := uniqueName(used, fieldPath)
+		itemName := uniqueName(used, sliceName+"Item")
+		decls = append(decls, fmt.Sprintf(`// %s is implemented by each variant of the elements of %s,
+// selected by their %q field.
+type %s interface {
+	is%s()
+}`, itemName, sliceName, discriminator, itemName, itemName))
+
+		var cases strings.Builder
+		for _, variant := range field.Variants {
+			variant.Name = uniqueName(used, variantTypeName(sliceName, variant.Key, variant.Config))
+			d, v := polymorphicDecls(variant, variant.Name, discriminator, used)
+			decls = append(decls, "type "+variant.String(),
+				fmt.Sprintf("func (%s) is%s() {}", variant.Name, itemName))
+			decls, variants = append(decls, d...), append(variants, v...)
+			variants = append(variants, variant)
+			fmt.Fprintf(&cases, "case %q:\nitem = &%s{}\n", variant.Key, variant.Name)
+		}
+
+		decls = append(decls, fmt.Sprintf(`// %s holds elements of any %s variant.
+type %s []%s
+
+// UnmarshalJSON decodes each element into the %s variant named by its
+// %q field.
+func (s *%s) UnmarshalJSON(data []byte) error {
+	var elems []json.RawMessage
+	if err := json.Unmarshal(data, &elems); err != nil {
+		return err
+	}
+	*s = make(%s, 0, len(elems))
+	for _, elem := range elems {
+		var d struct {
+			Value string `+"`json:%q`"+`
+		}
+		if err := json.Unmarshal(elem, &d); err != nil {
+			return err
+		}
+		var item %s
+		switch d.Value {
+		%sdefault:
+			return fmt.Errorf("unknown %s %%q", d.Value)
+		}
+		if err := json.Unmarshal(elem, item); err != nil {
+			return err
+		}
+		*s = append(*s, item)
+	}
+	return nil
+}`, sliceName, itemName, sliceName, itemName, itemName, discriminator, sliceName, sliceName,
+			discriminator, itemName, cases.String(), discriminator))
+
+		field.Type = sliceName
+		field.Repeated = 0
+		field.Children = nil
+	}
+	return decls, variants
+}
+
+// variantTypeName returns the name of the variant struct for discriminator
+// value key of the polymorphic slice type sliceName.
+func variantTypeName(sliceName, key string, cfg *Config) string {
+	return sliceName + fmtFieldName(key, cfg.Initialisms)
+}
@@ -0,0 +1,149 @@
+package jsonstruct
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// protoImports maps well-known protobuf types to the files declaring them.
+var protoImports = map[string]string{
+	"google.protobuf.Timestamp": "google/protobuf/timestamp.proto",
+	"google.protobuf.Struct":    "google/protobuf/struct.proto",
+	"google.protobuf.Value":     "google/protobuf/struct.proto",
+	"google.protobuf.ListValue": "google/protobuf/struct.proto",
+}
+
+// renderProto renders typ as a proto3 file in package pkgName declaring a
+// message named name. Nested objects become nested messages, and fields are
+// numbered in the order they are emitted.
+func renderProto(typ *Type, name, pkgName string) []byte {
+	imports := map[string]bool{}
+	var body strings.Builder
+	writeProtoMessage(&body, typ, name, 0, imports)
+
+	var b strings.Builder
+	b.WriteString("syntax = \"proto3\";\n\n")
+	fmt.Fprintf(&b, "package %s;\n\n", pkgName)
+	if len(imports) > 0 {
+		files := make([]string, 0, len(imports))
+		for file := range imports {
+			files = append(files, file)
+		}
+		sort.Strings(files)
+		for _, file := range files {
+			fmt.Fprintf(&b, "import %q;\n", file)
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString(body.String())
+	return []byte(b.String())
+}
+
+// writeProtoMessage writes a message named name for the struct type typ to
+// b, indented for nesting at the given depth. The files declaring the
+// well-known types it uses are added to imports.
+func writeProtoMessage(b *strings.Builder, typ *Type, name string, depth int, imports map[string]bool) {
+	indent := strings.Repeat("  ", depth)
+	fmt.Fprintf(b, "%smessage %s {\n", indent, name)
+	for _, field := range typ.Children {
+		if field.Type == "struct" && !strings.HasSuffix(protoFieldType(field), "ListValue") {
+			writeProtoMessage(b, field, protoMessageName(field), depth+1, imports)
+		}
+	}
+	for i, field := range typ.Children {
+		fieldType := protoFieldType(field)
+		if file, ok := protoImports[strings.TrimPrefix(fieldType, "repeated ")]; ok {
+			imports[file] = true
+		}
+		if strings.HasPrefix(fieldType, "map<") {
+			if file, ok := protoImports[strings.TrimSuffix(fieldType[len("map<string, "):], ">")]; ok {
+				imports[file] = true
+			}
+		}
+		optional := ""
+		if fieldType == protoScalarType(field) && !strings.HasPrefix(fieldType, "google.") && field.Type != "struct" &&
+			(field.Count < typ.objects() || field.Observed["null"] > 0) {
+			// distinguish missing and null scalars from zero values; message
+			// fields already have presence.
+			optional = "optional "
+		}
+		fieldName := protoFieldName(field.Key)
+		options := ""
+		if fieldName != field.Key && fmtTagName(field.Key, "camel") != field.Key {
+			// proto's JSON mapping would otherwise expect the lowerCamelCase
+			// name.
+			options = fmt.Sprintf(" [json_name = %q]", field.Key)
+		}
+		fmt.Fprintf(b, "%s  %s%s %s = %d%s;\n", indent, optional, fieldType, fieldName, i+1, options)
+	}
+	fmt.Fprintf(b, "%s}\n", indent)
+}
+
+// protoFieldType returns the protobuf type of the field t, including the
+// repeated label and map syntax.
+func protoFieldType(t *Type) string {
+	elem := protoScalarType(t)
+	if t.Type == "struct" {
+		elem = protoMessageName(t)
+	}
+	switch {
+	case t.Repeated > 1 || (t.Repeated > 0 && t.Map):
+		// proto has no nested repeated fields or repeated maps.
+		return "repeated google.protobuf.ListValue"
+	case t.Map:
+		return "map<string, " + elem + ">"
+	case t.Repeated == 1:
+		return "repeated " + elem
+	}
+	return elem
+}
+
+// protoScalarType returns the protobuf type of values of t, falling back to
+// google.protobuf.Value for values of unknown or conflicting type.
+func protoScalarType(t *Type) string {
+	switch t.Type {
+	case "string", "net.IP", "json.Number":
+		return "string"
+	case "float64":
+		return "double"
+	case "int64", "int":
+		return "int64"
+	case "bool":
+		return "bool"
+	case "[]byte":
+		return "bytes"
+	case "time.Time":
+		return "google.protobuf.Timestamp"
+	case "map[string]interface{}":
+		return "google.protobuf.Struct"
+	}
+	return "google.protobuf.Value"
+}
+
+// protoMessageName returns the name of the nested message generated for the
+// struct field t, singular for arrays and maps of objects.
+func protoMessageName(t *Type) string {
+	if t.Repeated > 0 || t.Map {
+		return singularize(t.Name)
+	}
+	return t.Name
+}
+
+// protoFieldName returns key in snake case, as protobuf field names are by
+// convention, made a valid identifier.
+//
+// Example:
+// 	protoFieldName("avatarURL")
+// Output: avatar_url
+func protoFieldName(key string) string {
+	name := fmtTagName(key, "snake")
+	if name == key && !identifier.MatchString(key) {
+		name = "field"
+	}
+	if r := []rune(name)[0]; !unicode.IsLetter(r) {
+		name = "f_" + name
+	}
+	return name
+}
@@ -0,0 +1,250 @@
+package jsonstruct
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"text/tabwriter"
+)
+
+// writeMergeReport writes a table to w summarizing, for each field of typ and
+// its nested structs, how often it was present, the type chosen for it and
+// any conflicting types it was observed as.
+func writeMergeReport(w io.Writer, typ *Type) {
+	var buf bytes.Buffer
+	tw := tabwriter.NewWriter(&buf, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "FIELD\tPRESENCE\tPOINTER\tTYPE\tCONFLICTS")
+	var walk func(t *Type, path string)
+	walk = func(t *Type, path string) {
+		for _, field := range t.Children {
+			fieldPath := path + "." + field.Name
+			pointer := "no"
+			if strings.HasPrefix(field.GetType(), "*") {
+				pointer = "yes"
+			}
+			fmt.Fprintf(tw, "%s\t%.1f%%\t%s\t%s\t%s\n", fieldPath,
+				100*float64(field.Count)/float64(t.objects()), pointer, field.GetType(), conflicts(field))
+			walk(field, fieldPath)
+		}
+	}
+	walk(typ, typ.Name)
+	tw.Flush()
+	for _, line := range strings.SplitAfter(buf.String(), "\n") {
+		// drop the padding tabwriter leaves on rows without conflicts.
+		if trimmed := strings.TrimRight(line, " \n"); trimmed != "" {
+			fmt.Fprintln(w, trimmed)
+		}
+	}
+}
+
+// conflicts describes the types t was observed as, or returns an empty
+// string if it was only observed as one type.
+func conflicts(t *Type) string {
+	if len(t.Observed) < 2 {
+		return ""
+	}
+	types := make([]string, 0, len(t.Observed))
+	for typ := range t.Observed {
+		types = append(types, typ)
+	}
+	sort.Strings(types)
+	parts := make([]string, 0, len(types))
+	for _, typ := range types {
+		parts = append(parts, fmt.Sprintf("%s (%d)", typ, t.Observed[typ]))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// writeSourceMap writes a JSON object to w mapping the Go path of each field
+// of typ, its nested structs and its polymorphic variants to the JSON path
+// of the value it holds. rootPath is the JSON path of typ itself.
+func writeSourceMap(w io.Writer, typ *Type, rootPath string) error {
+	paths := map[string]string{}
+	var walk func(t *Type, goPath, jsonPath string)
+	walk = func(t *Type, goPath, jsonPath string) {
+		for _, field := range t.Children {
+			fieldGoPath := goPath + "." + field.Name
+			fieldJSONPath := jsonPath + jsonPathKey(field.Key) + strings.Repeat("[*]", field.Repeated)
+			if field.Map {
+				fieldJSONPath += ".*"
+			}
+			paths[fieldGoPath] = fieldJSONPath
+			if len(field.Variants) > 1 {
+				for _, variant := range field.Variants {
+					walk(variant, variantTypeName(goPath+field.Name, variant.Key, variant.Config), fieldJSONPath)
+				}
+				continue
+			}
+			walk(field, fieldGoPath, fieldJSONPath)
+		}
+	}
+	walk(typ, typ.Name, rootPath)
+	data, err := json.MarshalIndent(paths, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "%s\n", data)
+	return err
+}
+
+var identifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// jsonPathKey returns the JSON path segment selecting key, in dot notation
+// where possible and bracket notation otherwise.
+func jsonPathKey(key string) string {
+	if identifier.MatchString(key) {
+		return "." + key
+	}
+	return "['" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(key) + "']"
+}
+
+// exampleJSON returns a compact JSON value matching typ, using the observed
+// example values where known and a zero value of the type otherwise. Object
+// members follow the order of the fields.
+func exampleJSON(typ *Type) string {
+	var value string
+	switch {
+	case typ.Type == "struct":
+		parts := make([]string, 0, len(typ.Children))
+		for _, field := range typ.Children {
+			key, _ := json.Marshal(field.Key)
+			parts = append(parts, string(key)+":"+exampleJSON(field))
+		}
+		value = "{" + strings.Join(parts, ",") + "}"
+	case typ.Example != nil:
+		data, _ := json.Marshal(typ.Example)
+		value = string(data)
+	default:
+		switch typ.Type {
+		case "string":
+			value = `""`
+		case "float64", "int64", "int", "json.Number":
+			value = "0"
+		case "bool":
+			value = "false"
+		case "time.Time":
+			value = `"0001-01-01T00:00:00Z"`
+		case "net.IP":
+			value = `"127.0.0.1"`
+		case "[]byte":
+			value = `"AA=="`
+		case "map[string]interface{}":
+			value = "{}"
+		default:
+			value = "null"
+		}
+	}
+	if typ.Map {
+		value = `{"key":` + value + "}"
+	}
+	return strings.Repeat("[", typ.Repeated) + value + strings.Repeat("]", typ.Repeated)
+}
+
+// writeExample writes the compact JSON document example to w, indented.
+func writeExample(w io.Writer, example string) error {
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, []byte(example), "", "  "); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintf(w, "%s\n", buf.Bytes())
+	return err
+}
+
+// histogramBuckets is the maximum number of buckets in a histogram, and
+// histogramWidth the length of the longest bar.
+const (
+	histogramBuckets = 10
+	histogramWidth   = 40
+)
+
+// writeHistograms writes an ASCII histogram of the observed values of each
+// numeric field of typ, and of its nested structs, to w.
+func writeHistograms(w io.Writer, typ *Type) {
+	var walk func(t *Type, path string)
+	walk = func(t *Type, path string) {
+		for _, field := range t.Children {
+			fieldPath := path + "." + field.Name
+			if len(field.Numbers) > 0 {
+				writeHistogram(w, fieldPath, field.Numbers, field.NumberCount)
+			}
+			walk(field, fieldPath)
+		}
+	}
+	walk(typ, typ.Name)
+}
+
+// writeHistogram writes a histogram of values, a sample of count values if
+// there are fewer of them, titled name, to w. Values are split into equal-width
+// buckets between their minimum and maximum, with no more buckets than
+// distinct values. The title also gives the median, 90th and 99th percentiles,
+// which are approximate when values is a sample.
+func writeHistogram(w io.Writer, name string, values []float64, count int) {
+	min, max := math.Inf(1), math.Inf(-1)
+	distinct := map[float64]bool{}
+	for _, v := range values {
+		min, max = math.Min(min, v), math.Max(max, v)
+		distinct[v] = true
+	}
+	buckets := histogramBuckets
+	if len(distinct) < buckets {
+		buckets = len(distinct)
+	}
+	width := (max - min) / float64(buckets)
+	counts := make([]int, buckets)
+	for _, v := range values {
+		i := buckets - 1
+		if width > 0 {
+			i = int((v - min) / width)
+		}
+		if i >= buckets {
+			i = buckets - 1
+		}
+		counts[i]++
+	}
+	most := 0
+	for _, n := range counts {
+		if n > most {
+			most = n
+		}
+	}
+
+	p := percentiles(values, 50, 90, 99)
+	fmt.Fprintf(w, "%s (n=%d, distinct=%d, min=%g, max=%g, p50=%g, p90=%g, p99=%g)\n", name, count, len(distinct), min, max, p[0], p[1], p[2])
+	var buf bytes.Buffer
+	tw := tabwriter.NewWriter(&buf, 0, 8, 2, ' ', 0)
+	for i, n := range counts {
+		lo, hi := min+float64(i)*width, min+float64(i+1)*width
+		if i == buckets-1 {
+			hi = max
+		}
+		bar := strings.Repeat("#", (n*histogramWidth+most-1)/most)
+		fmt.Fprintf(tw, "  %.4g - %.4g\t%d\t%s\n", lo, hi, n, bar)
+	}
+	tw.Flush()
+	for _, line := range strings.SplitAfter(buf.String(), "\n") {
+		if trimmed := strings.TrimRight(line, " \n"); trimmed != "" {
+			fmt.Fprintln(w, trimmed)
+		}
+	}
+}
+
+// percentiles returns the given percentiles, from 0 to 100, of values by the
+// nearest-rank method. values is left unsorted.
+func percentiles(values []float64, ps ...float64) []float64 {
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	result := make([]float64, len(ps))
+	for i, p := range ps {
+		rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+		if rank < 1 {
+			rank = 1
+		}
+		result[i] = sorted[rank-1]
+	}
+	return result
+}
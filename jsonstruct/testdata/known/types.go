@@ -0,0 +1,16 @@
+package known
+
+type Address struct {
+	Street string `json:"street"`
+	City   string `json:"city,omitempty"`
+	Zip    string
+	note   string
+	Secret string `json:"-"`
+}
+
+type Money struct {
+	Amount   int64  `json:"amount"`
+	Currency string `json:"currency"`
+}
+
+type ID string
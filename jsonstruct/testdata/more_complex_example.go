@@ -8,21 +8,21 @@ type more_complex_example struct {
 	CreatedAt         string      `json:"created_at,omitempty"`
 	Email             string      `json:"email,omitempty"`
 	EventsURL         string      `json:"events_url,omitempty"`
-	Followers         float64     `json:"followers,omitempty"`
+	Followers         int64       `json:"followers,omitempty"`
 	FollowersURL      string      `json:"followers_url,omitempty"`
-	Following         float64     `json:"following,omitempty"`
+	Following         int64       `json:"following,omitempty"`
 	FollowingURL      string      `json:"following_url,omitempty"`
 	GistsURL          string      `json:"gists_url,omitempty"`
 	GravatarID        string      `json:"gravatar_id,omitempty"`
 	Hireable          bool        `json:"hireable,omitempty"`
-	HtmlURL           string      `json:"html_url,omitempty"`
-	ID                float64     `json:"id,omitempty"`
+	HTMLURL           string      `json:"html_url,omitempty"`
+	ID                int64       `json:"id,omitempty"`
 	Location          string      `json:"location,omitempty"`
 	Login             string      `json:"login,omitempty"`
 	Name              string      `json:"name,omitempty"`
 	OrganizationsURL  string      `json:"organizations_url,omitempty"`
-	PublicGists       float64     `json:"public_gists,omitempty"`
-	PublicRepos       float64     `json:"public_repos,omitempty"`
+	PublicGists       int64       `json:"public_gists,omitempty"`
+	PublicRepos       int64       `json:"public_repos,omitempty"`
 	ReceivedEventsURL string      `json:"received_events_url,omitempty"`
 	ReposURL          string      `json:"repos_url,omitempty"`
 	StarredURL        string      `json:"starred_url,omitempty"`
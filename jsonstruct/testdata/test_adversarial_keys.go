@@ -0,0 +1,9 @@
+package test_package
+
+type test_adversarial_keys struct {
+	Back_Slash   int64 `json:"back\\slash,omitempty"`
+	Back_Tick    int64 "json:\"back`tick,omitempty\""
+	Double_Quote int64 `json:"double\"quote,omitempty"`
+	End__Comment int64 `json:"end*/comment,omitempty"`
+	New_Line     int64 `json:"new\nline,omitempty"`
+}
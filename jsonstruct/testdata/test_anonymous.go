@@ -1,6 +1,4 @@
-package test_package
-
-type test_nested_json struct {
+struct {
 	Baz []float64 `json:"baz,omitempty"`
 	Foo struct {
 		Bar float64 `json:"bar,omitempty"`
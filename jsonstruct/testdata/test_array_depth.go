@@ -0,0 +1,11 @@
+package test_package
+
+type test_array_depth struct {
+	Deep   [][][]interface{} `json:"deep,omitempty"`
+	Matrix [][]int64         `json:"matrix,omitempty"`
+	Points [][]struct {
+		X int64 `json:"x,omitempty"`
+	} `json:"points,omitempty"`
+	Tensor [][][]int64 `json:"tensor,omitempty"`
+	Vector []int64     `json:"vector,omitempty"`
+}
@@ -0,0 +1,11 @@
+package test_package
+
+type test_array_depth_capped struct {
+	Deep   [][]interface{} `json:"deep,omitempty"`
+	Matrix [][]float64     `json:"matrix,omitempty"`
+	Points [][]struct {
+		X float64 `json:"x,omitempty"`
+	} `json:"points,omitempty"`
+	Tensor [][]interface{} `json:"tensor,omitempty"`
+	Vector []float64       `json:"vector,omitempty"`
+}
@@ -0,0 +1,13 @@
+package test_package
+
+type test_array_union struct {
+	Items []struct {
+		A   *int64  `json:"a,omitempty"`
+		B   *string `json:"b,omitempty"`
+		C   *bool   `json:"c,omitempty"`
+		Sub []struct {
+			X *int64 `json:"x,omitempty"`
+			Y *int64 `json:"y,omitempty"`
+		} `json:"sub,omitempty"`
+	} `json:"items,omitempty"`
+}
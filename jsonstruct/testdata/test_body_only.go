@@ -0,0 +1,7 @@
+type test_body_only struct {
+	Client net.IP   `json:"client,omitempty"`
+	Host   string   `json:"host,omitempty"`
+	Mixed  []string `json:"mixed,omitempty"`
+	Peers  []net.IP `json:"peers,omitempty"`
+	Server net.IP   `json:"server,omitempty"`
+}
@@ -0,0 +1,18 @@
+package test_package
+
+type test_canonical struct {
+	Active  bool `json:"active,omitempty"`
+	Address struct {
+		City string `json:"city,omitempty"`
+		Geo  struct {
+			Lat float64 `json:"lat,omitempty"`
+		} `json:"geo,omitempty"`
+		Tags []string `json:"tags,omitempty"`
+	} `json:"address,omitempty"`
+	Codes []float64 `json:"codes,omitempty"`
+	Items []struct {
+		ID float64 `json:"id,omitempty"`
+	} `json:"items,omitempty"`
+	Name string  `json:"name,omitempty"`
+	Zeta float64 `json:"zeta,omitempty"`
+}
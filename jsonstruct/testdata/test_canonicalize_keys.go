@@ -0,0 +1,9 @@
+package test_package
+
+type test_canonicalize_keys struct {
+	Address struct {
+		ZipCode string `json:"zip_code,omitempty"`
+	} `json:"address,omitempty"`
+	DisplayName string  `json:"display_name,omitempty"`
+	UserID      float64 `json:"user_id,omitempty"`
+}
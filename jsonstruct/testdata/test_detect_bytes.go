@@ -0,0 +1,9 @@
+package test_package
+
+type test_detect_bytes struct {
+	Avatar []byte `json:"avatar,omitempty"`
+	Hash   string `json:"hash,omitempty"`
+	Mixed  []byte `json:"mixed,omitempty"`
+	Sig    string `json:"sig,omitempty"`
+	Word   string `json:"word,omitempty"`
+}
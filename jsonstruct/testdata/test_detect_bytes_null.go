@@ -0,0 +1,6 @@
+package test_package
+
+type test_detect_bytes_null struct {
+	Avatar []byte   `json:"avatar,omitempty"`
+	Thumbs [][]byte `json:"thumbs,omitempty"`
+}
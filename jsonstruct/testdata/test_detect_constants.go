@@ -0,0 +1,15 @@
+package test_package
+
+type test_detect_constants struct {
+	// constant: true
+	Active bool    `json:"active,omitempty"`
+	ID     float64 `json:"id,omitempty"`
+	Meta   struct {
+		// constant: 2
+		Schema float64 `json:"schema,omitempty"`
+		Source string  `json:"source,omitempty"`
+	} `json:"meta,omitempty"`
+	Tags []string `json:"tags,omitempty"`
+	// constant: "v1"
+	Version string `json:"version,omitempty"`
+}
@@ -0,0 +1,13 @@
+package test_package
+
+import (
+	"net"
+)
+
+type test_detect_ip struct {
+	Client net.IP   `json:"client,omitempty"`
+	Host   string   `json:"host,omitempty"`
+	Mixed  []string `json:"mixed,omitempty"`
+	Peers  []net.IP `json:"peers,omitempty"`
+	Server net.IP   `json:"server,omitempty"`
+}
@@ -0,0 +1,15 @@
+package test_package
+
+type test_detect_maps struct {
+	Scores   map[string]float64 `json:"scores,omitempty"`
+	Settings struct {
+		Font_Size string `json:"font-size,omitempty"`
+		Lang      string `json:"lang,omitempty"`
+		Theme     string `json:"theme,omitempty"`
+	} `json:"settings,omitempty"`
+	Users map[string]struct {
+		Admin *bool   `json:"admin,omitempty"`
+		Email *string `json:"email,omitempty"`
+		Name  string  `json:"name,omitempty"`
+	} `json:"users,omitempty"`
+}
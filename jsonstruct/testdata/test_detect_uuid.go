@@ -0,0 +1,12 @@
+package test_package
+
+import (
+	"github.com/google/uuid"
+)
+
+type test_detect_uuid struct {
+	ID   uuid.UUID   `json:"id,omitempty"`
+	Name string      `json:"name,omitempty"`
+	Ref  string      `json:"ref,omitempty"`
+	Tags []uuid.UUID `json:"tags,omitempty"`
+}
@@ -0,0 +1,10 @@
+package test_package
+
+import (
+	"github.com/google/uuid"
+)
+
+type test_detect_uuid_null struct {
+	ID     uuid.UUID  `json:"id,omitempty"`
+	Parent *uuid.UUID `json:"parent,omitempty"`
+}
@@ -0,0 +1,39 @@
+package test_package
+
+import (
+	"encoding/json"
+
+	"github.com/google/uuid"
+)
+
+type test_detect_uuid_nullable struct {
+	ID     uuid.UUID           `json:"id,omitempty"`
+	Parent Nullable[uuid.UUID] `json:"parent,omitempty"`
+}
+
+// Nullable holds a JSON value that may be null or missing. Valid reports
+// whether a value was present. An invalid Nullable is encoded as null, so a
+// missing field round-trips as an explicit null.
+type Nullable[T any] struct {
+	Value T
+	Valid bool
+}
+
+func (n *Nullable[T]) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		*n = Nullable[T]{}
+		return nil
+	}
+	if err := json.Unmarshal(data, &n.Value); err != nil {
+		return err
+	}
+	n.Valid = true
+	return nil
+}
+
+func (n Nullable[T]) MarshalJSON() ([]byte, error) {
+	if !n.Valid {
+		return []byte("null"), nil
+	}
+	return json.Marshal(n.Value)
+}
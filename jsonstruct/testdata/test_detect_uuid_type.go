@@ -0,0 +1,12 @@
+package test_package
+
+import (
+	"github.com/gofrs/uuid/v5"
+)
+
+type test_detect_uuid_type struct {
+	ID   uuid.UUID   `json:"id,omitempty"`
+	Name string      `json:"name,omitempty"`
+	Ref  string      `json:"ref,omitempty"`
+	Tags []uuid.UUID `json:"tags,omitempty"`
+}
@@ -0,0 +1,13 @@
+package test_package
+
+type User struct {
+	ID      int64   `json:"id"`
+	Name    *string `json:"name"`
+	Score   float64 `json:"score"`
+	Removed bool    `json:"removed"`
+	Owner   Owner   `json:"owner"`
+}
+
+type Owner struct {
+	Admin int `json:"admin"`
+}
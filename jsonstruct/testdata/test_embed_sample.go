@@ -0,0 +1,14 @@
+package test_package
+
+import (
+	_ "embed"
+)
+
+type test_embed_sample struct {
+	F_O_O float64 `json:"f.o-o,omitempty"`
+}
+
+// test_embed_sampleSample holds the sample JSON test_embed_sample was generated from.
+//
+//go:embed testdata/sample.json
+var test_embed_sampleSample []byte
@@ -0,0 +1,18 @@
+package test_package
+
+type test_emit_constants struct {
+	ID   float64 `json:"id,omitempty"`
+	Meta struct {
+		Source string `json:"source,omitempty"`
+	} `json:"meta,omitempty"`
+	Tags []string `json:"tags,omitempty"`
+}
+
+// test_emit_constantsActive is the constant value of the "active" field.
+const test_emit_constantsActive = true
+
+// test_emit_constantsMetaSchema is the constant value of the "schema" field.
+const test_emit_constantsMetaSchema = 2
+
+// test_emit_constantsVersion is the constant value of the "version" field.
+const test_emit_constantsVersion = "v1"
@@ -0,0 +1,12 @@
+package test_package
+
+type test_empty_object_as_map struct {
+	ID    float64 `json:"id,omitempty"`
+	Items []struct {
+		Extra map[string]interface{} `json:"extra,omitempty"`
+	} `json:"items,omitempty"`
+	Labels map[string]interface{} `json:"labels,omitempty"`
+	Meta   struct {
+		Owner string `json:"owner,omitempty"`
+	} `json:"meta,omitempty"`
+}
@@ -0,0 +1,62 @@
+package test_package
+
+type test_enums struct {
+	Code test_enumsCode `json:"code,omitempty"`
+	ID   int64          `json:"id,omitempty"`
+	Kind test_enumsKind `json:"kind,omitempty"`
+	Meta struct {
+		Region test_enumsMetaRegion `json:"region,omitempty"`
+	} `json:"meta,omitempty"`
+	Name   string           `json:"name,omitempty"`
+	Status test_enumsStatus `json:"status,omitempty"`
+	Tags   []test_enumsTag  `json:"tags,omitempty"`
+}
+
+// test_enumsCode is the type of the "code" field.
+type test_enumsCode string
+
+// Values of test_enumsCode observed in the input.
+const (
+	test_enumsCode200 test_enumsCode = "200"
+	test_enumsCode404 test_enumsCode = "404"
+	test_enumsCode500 test_enumsCode = "500"
+)
+
+// test_enumsKind is the type of the "kind" field.
+type test_enumsKind string
+
+// Values of test_enumsKind observed in the input.
+const (
+	test_enumsKindUser  test_enumsKind = "user"
+	test_enumsKindEmpty test_enumsKind = ""
+	test_enumsKindAdmin test_enumsKind = "admin"
+)
+
+// test_enumsMetaRegion is the type of the "region" field.
+type test_enumsMetaRegion string
+
+// Values of test_enumsMetaRegion observed in the input.
+const (
+	test_enumsMetaRegionUsEast test_enumsMetaRegion = "us-east"
+	test_enumsMetaRegionEuWest test_enumsMetaRegion = "eu-west"
+)
+
+// test_enumsStatus is the type of the "status" field.
+type test_enumsStatus string
+
+// Values of test_enumsStatus observed in the input.
+const (
+	test_enumsStatusActive     test_enumsStatus = "active"
+	test_enumsStatusPending    test_enumsStatus = "pending"
+	test_enumsStatusInProgress test_enumsStatus = "in progress"
+)
+
+// test_enumsTag is the type of the "tags" field.
+type test_enumsTag string
+
+// Values of test_enumsTag observed in the input.
+const (
+	test_enumsTagA test_enumsTag = "a"
+	test_enumsTagB test_enumsTag = "b"
+	test_enumsTagC test_enumsTag = "c"
+)
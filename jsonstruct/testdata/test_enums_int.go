@@ -0,0 +1,37 @@
+package test_package
+
+type test_enums_int struct {
+	ID       int64                    `json:"id,omitempty"`
+	Kind     test_enums_intKind       `json:"kind,omitempty"`
+	Level    test_enums_intLevel      `json:"level,omitempty"`
+	Priority []test_enums_intPriority `json:"priority,omitempty"`
+	Score    float64                  `json:"score,omitempty"`
+}
+
+// test_enums_intKind is the type of the "kind" field.
+type test_enums_intKind int64
+
+// Values of test_enums_intKind observed in the input.
+const (
+	test_enums_intKind0 test_enums_intKind = 0
+	test_enums_intKind2 test_enums_intKind = 2
+)
+
+// test_enums_intLevel is the type of the "level" field.
+type test_enums_intLevel int64
+
+// Values of test_enums_intLevel observed in the input.
+const (
+	test_enums_intLevelNeg1 test_enums_intLevel = -1
+	test_enums_intLevel0    test_enums_intLevel = 0
+	test_enums_intLevel1    test_enums_intLevel = 1
+)
+
+// test_enums_intPriority is the type of the "priority" field.
+type test_enums_intPriority int64
+
+// Values of test_enums_intPriority observed in the input.
+const (
+	test_enums_intPriority1 test_enums_intPriority = 1
+	test_enums_intPriority3 test_enums_intPriority = 3
+)
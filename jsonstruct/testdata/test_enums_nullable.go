@@ -0,0 +1,46 @@
+package test_package
+
+import (
+	"encoding/json"
+)
+
+type test_enums_nullable struct {
+	ID     int64                               `json:"id,omitempty"`
+	Status Nullable[test_enums_nullableStatus] `json:"status,omitempty"`
+}
+
+// Nullable holds a JSON value that may be null or missing. Valid reports
+// whether a value was present. An invalid Nullable is encoded as null, so a
+// missing field round-trips as an explicit null.
+type Nullable[T any] struct {
+	Value T
+	Valid bool
+}
+
+func (n *Nullable[T]) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		*n = Nullable[T]{}
+		return nil
+	}
+	if err := json.Unmarshal(data, &n.Value); err != nil {
+		return err
+	}
+	n.Valid = true
+	return nil
+}
+
+func (n Nullable[T]) MarshalJSON() ([]byte, error) {
+	if !n.Valid {
+		return []byte("null"), nil
+	}
+	return json.Marshal(n.Value)
+}
+
+// test_enums_nullableStatus is the type of the "status" field.
+type test_enums_nullableStatus string
+
+// Values of test_enums_nullableStatus observed in the input.
+const (
+	test_enums_nullableStatusOpen   test_enums_nullableStatus = "open"
+	test_enums_nullableStatusClosed test_enums_nullableStatus = "closed"
+)
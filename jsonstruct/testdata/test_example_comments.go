@@ -0,0 +1,33 @@
+package test_package
+
+type test_example_comments struct {
+	AvatarURL         string      `json:"avatar_url,omitempty"` // e.g. "https://1.gravatar.com/avatar/68f004984...
+	Bio               interface{} `json:"bio,omitempty"`
+	Blog              string      `json:"blog,omitempty"`                // e.g. ""
+	Company           string      `json:"company,omitempty"`             // e.g. "Facebook"
+	CreatedAt         string      `json:"created_at,omitempty"`          // e.g. "2008-03-27T15:49:13Z"
+	Email             string      `json:"email,omitempty"`               // e.g. "travis.cline@gmail.com"
+	EventsURL         string      `json:"events_url,omitempty"`          // e.g. "https://api.github.com/users/tmc/events...
+	Followers         int64       `json:"followers,omitempty"`           // e.g. 103
+	FollowersURL      string      `json:"followers_url,omitempty"`       // e.g. "https://api.github.com/users/tmc/follow...
+	Following         int64       `json:"following,omitempty"`           // e.g. 88
+	FollowingURL      string      `json:"following_url,omitempty"`       // e.g. "https://api.github.com/users/tmc/follow...
+	GistsURL          string      `json:"gists_url,omitempty"`           // e.g. "https://api.github.com/users/tmc/gists{...
+	GravatarID        string      `json:"gravatar_id,omitempty"`         // e.g. "68f0049842700597b89972e1fbf6f542"
+	Hireable          bool        `json:"hireable,omitempty"`            // e.g. true
+	HTMLURL           string      `json:"html_url,omitempty"`            // e.g. "https://github.com/tmc"
+	ID                int64       `json:"id,omitempty"`                  // e.g. 3977
+	Location          string      `json:"location,omitempty"`            // e.g. "lawrence, ks"
+	Login             string      `json:"login,omitempty"`               // e.g. "tmc"
+	Name              string      `json:"name,omitempty"`                // e.g. "Travis Cline"
+	OrganizationsURL  string      `json:"organizations_url,omitempty"`   // e.g. "https://api.github.com/users/tmc/orgs"
+	PublicGists       int64       `json:"public_gists,omitempty"`        // e.g. 44
+	PublicRepos       int64       `json:"public_repos,omitempty"`        // e.g. 87
+	ReceivedEventsURL string      `json:"received_events_url,omitempty"` // e.g. "https://api.github.com/users/tmc/receiv...
+	ReposURL          string      `json:"repos_url,omitempty"`           // e.g. "https://api.github.com/users/tmc/repos"
+	StarredURL        string      `json:"starred_url,omitempty"`         // e.g. "https://api.github.com/users/tmc/starre...
+	SubscriptionsURL  string      `json:"subscriptions_url,omitempty"`   // e.g. "https://api.github.com/users/tmc/subscr...
+	Type              string      `json:"type,omitempty"`                // e.g. "User"
+	UpdatedAt         string      `json:"updated_at,omitempty"`          // e.g. "2013-09-05T00:03:43Z"
+	URL               string      `json:"url,omitempty"`                 // e.g. "https://api.github.com/users/tmc"
+}
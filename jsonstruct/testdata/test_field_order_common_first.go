@@ -0,0 +1,11 @@
+package test_package
+
+type test_field_order_common_first struct {
+	ID    int64       `json:"id,omitempty"`
+	Score interface{} `json:"score,omitempty"`
+	Name  interface{} `json:"name,omitempty"`
+	Owner struct {
+		Login string `json:"login,omitempty"`
+		Admin bool   `json:"admin,omitempty"`
+	} `json:"owner,omitempty"`
+}
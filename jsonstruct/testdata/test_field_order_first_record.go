@@ -0,0 +1,14 @@
+package test_package
+
+type test_field_order_first_record struct {
+	ID    float64 `json:"id,omitempty"`
+	Name  string  `json:"name,omitempty"`
+	Owner struct {
+		Login string  `json:"login,omitempty"`
+		ID    float64 `json:"id,omitempty"`
+		Admin bool    `json:"admin,omitempty"`
+	} `json:"owner,omitempty"`
+	CreatedAt string  `json:"created_at,omitempty"`
+	Beta      float64 `json:"beta,omitempty"`
+	Zeta      bool    `json:"zeta,omitempty"`
+}
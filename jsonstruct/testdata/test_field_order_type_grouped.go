@@ -0,0 +1,18 @@
+package test_package
+
+type test_field_order_type_grouped struct {
+	Active bool      `json:"active,omitempty"`
+	Name   string    `json:"name,omitempty"`
+	Zeta   float64   `json:"zeta,omitempty"`
+	Codes  []float64 `json:"codes,omitempty"`
+	Items  []struct {
+		ID float64 `json:"id,omitempty"`
+	} `json:"items,omitempty"`
+	Address struct {
+		City string   `json:"city,omitempty"`
+		Tags []string `json:"tags,omitempty"`
+		Geo  struct {
+			Lat float64 `json:"lat,omitempty"`
+		} `json:"geo,omitempty"`
+	} `json:"address,omitempty"`
+}
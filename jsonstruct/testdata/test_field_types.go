@@ -0,0 +1,16 @@
+package test_package
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+type test_field_types struct {
+	ID   uint64 `json:"id,omitempty"`
+	Meta struct {
+		At *time.Duration `json:"at,omitempty"`
+		ID uint64         `json:"id,omitempty"`
+	} `json:"meta,omitempty"`
+	Price decimal.Decimal `json:"price,omitempty"`
+}
@@ -0,0 +1,57 @@
+package test_package
+
+import (
+	"encoding/json"
+)
+
+type test_flexible_types struct {
+	F FlexibleFloat64 `json:"f,omitempty"`
+	M interface{}     `json:"m,omitempty"`
+	N FlexibleInt64   `json:"n,omitempty"`
+	P []interface{}   `json:"p,omitempty"`
+	S interface{}     `json:"s,omitempty"`
+}
+
+// FlexibleFloat64 decodes a JSON number, or a JSON string holding one, into its
+// underlying type float64. An empty string decodes as zero.
+type FlexibleFloat64 float64
+
+// UnmarshalJSON decodes a JSON number or numeric string into f.
+func (f *FlexibleFloat64) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		if s == "" {
+			*f = 0
+			return nil
+		}
+		data = []byte(s)
+	}
+	var v float64
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	*f = FlexibleFloat64(v)
+	return nil
+}
+
+// FlexibleInt64 decodes a JSON number, or a JSON string holding one, into its
+// underlying type int64. An empty string decodes as zero.
+type FlexibleInt64 int64
+
+// UnmarshalJSON decodes a JSON number or numeric string into f.
+func (f *FlexibleInt64) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		if s == "" {
+			*f = 0
+			return nil
+		}
+		data = []byte(s)
+	}
+	var v int64
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	*f = FlexibleInt64(v)
+	return nil
+}
@@ -0,0 +1,7 @@
+package test_package
+
+type test_format_form struct {
+	Age  float64  `json:"age,omitempty"`
+	Role []string `json:"role,omitempty"`
+	User string   `json:"user,omitempty"`
+}
@@ -0,0 +1,11 @@
+package test_package
+
+type test_format_query struct {
+	Limit  float64  `json:"limit,omitempty"`
+	Offset string   `json:"offset,omitempty"`
+	Page   float64  `json:"page,omitempty"`
+	Q      string   `json:"q,omitempty"`
+	Safe   bool     `json:"safe,omitempty"`
+	Sort   string   `json:"sort,omitempty"`
+	Tag    []string `json:"tag,omitempty"`
+}
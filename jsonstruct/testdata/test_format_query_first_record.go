@@ -0,0 +1,11 @@
+package test_package
+
+type test_format_query_first_record struct {
+	Q      string   `json:"q,omitempty"`
+	Page   float64  `json:"page,omitempty"`
+	Tag    []string `json:"tag,omitempty"`
+	Safe   bool     `json:"safe,omitempty"`
+	Limit  float64  `json:"limit,omitempty"`
+	Offset string   `json:"offset,omitempty"`
+	Sort   string   `json:"sort,omitempty"`
+}
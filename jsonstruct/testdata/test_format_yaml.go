@@ -0,0 +1,13 @@
+package test_package
+
+type test_format_yaml struct {
+	Created string `json:"created,omitempty"`
+	Enabled bool   `json:"enabled,omitempty"`
+	Labels  struct {
+		App string `json:"app,omitempty"`
+	} `json:"labels,omitempty"`
+	Name     string  `json:"name,omitempty"`
+	Ports    []int64 `json:"ports,omitempty"`
+	Ratio    float64 `json:"ratio,omitempty"`
+	Replicas int64   `json:"replicas,omitempty"`
+}
@@ -0,0 +1,13 @@
+package test_package
+
+type test_format_yaml_first_record struct {
+	Name     string  `json:"name,omitempty"`
+	Replicas int64   `json:"replicas,omitempty"`
+	Ratio    float64 `json:"ratio,omitempty"`
+	Created  string  `json:"created,omitempty"`
+	Ports    []int64 `json:"ports,omitempty"`
+	Labels   struct {
+		App string `json:"app,omitempty"`
+	} `json:"labels,omitempty"`
+	Enabled bool `json:"enabled,omitempty"`
+}
@@ -0,0 +1,16 @@
+package test_package
+
+type test_gen_constructor struct {
+	Foo []struct {
+		Bar float64 `json:"bar,omitempty"`
+	} `json:"foo,omitempty"`
+}
+
+// Newtest_gen_constructor returns a new test_gen_constructor with its slice fields initialized to empty slices.
+func Newtest_gen_constructor() *test_gen_constructor {
+	return &test_gen_constructor{
+		Foo: []struct {
+			Bar float64 `json:"bar,omitempty"`
+		}{},
+	}
+}
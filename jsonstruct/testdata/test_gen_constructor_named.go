@@ -0,0 +1,49 @@
+package test_package
+
+type test_gen_constructor_named struct {
+	Count int64                            `json:"count,omitempty"`
+	Items []test_gen_constructor_namedItem `json:"items,omitempty"`
+	Owner test_gen_constructor_namedOwner  `json:"owner,omitempty"`
+}
+
+type test_gen_constructor_namedItem struct {
+	X int64 `json:"x,omitempty"`
+}
+
+type test_gen_constructor_namedOwner struct {
+	Meta test_gen_constructor_namedOwnerMeta `json:"meta,omitempty"`
+	Name string                              `json:"name,omitempty"`
+	Tags []string                            `json:"tags,omitempty"`
+}
+
+type test_gen_constructor_namedOwnerMeta struct {
+	Ids []int64 `json:"ids,omitempty"`
+}
+
+// Newtest_gen_constructor_named returns a new test_gen_constructor_named with its slice fields initialized to empty slices.
+func Newtest_gen_constructor_named() *test_gen_constructor_named {
+	return &test_gen_constructor_named{
+		Items: []test_gen_constructor_namedItem{},
+		Owner: *Newtest_gen_constructor_namedOwner(),
+	}
+}
+
+// Newtest_gen_constructor_namedItem returns a new test_gen_constructor_namedItem with its slice fields initialized to empty slices.
+func Newtest_gen_constructor_namedItem() *test_gen_constructor_namedItem {
+	return &test_gen_constructor_namedItem{}
+}
+
+// Newtest_gen_constructor_namedOwner returns a new test_gen_constructor_namedOwner with its slice fields initialized to empty slices.
+func Newtest_gen_constructor_namedOwner() *test_gen_constructor_namedOwner {
+	return &test_gen_constructor_namedOwner{
+		Meta: *Newtest_gen_constructor_namedOwnerMeta(),
+		Tags: []string{},
+	}
+}
+
+// Newtest_gen_constructor_namedOwnerMeta returns a new test_gen_constructor_namedOwnerMeta with its slice fields initialized to empty slices.
+func Newtest_gen_constructor_namedOwnerMeta() *test_gen_constructor_namedOwnerMeta {
+	return &test_gen_constructor_namedOwnerMeta{
+		Ids: []int64{},
+	}
+}
@@ -0,0 +1,38 @@
+package test_package
+
+type test_gen_constructor_nested struct {
+	Count int64 `json:"count,omitempty"`
+	Items []struct {
+		X int64 `json:"x,omitempty"`
+	} `json:"items,omitempty"`
+	Owner struct {
+		Meta struct {
+			Ids []int64 `json:"ids,omitempty"`
+		} `json:"meta,omitempty"`
+		Name string   `json:"name,omitempty"`
+		Tags []string `json:"tags,omitempty"`
+	} `json:"owner,omitempty"`
+}
+
+// Newtest_gen_constructor_nested returns a new test_gen_constructor_nested with its slice fields initialized to empty slices.
+func Newtest_gen_constructor_nested() *test_gen_constructor_nested {
+	return &test_gen_constructor_nested{
+		Items: []struct {
+			X int64 `json:"x,omitempty"`
+		}{},
+		Owner: struct {
+			Meta struct {
+				Ids []int64 `json:"ids,omitempty"`
+			} `json:"meta,omitempty"`
+			Name string   `json:"name,omitempty"`
+			Tags []string `json:"tags,omitempty"`
+		}{
+			Meta: struct {
+				Ids []int64 `json:"ids,omitempty"`
+			}{
+				Ids: []int64{},
+			},
+			Tags: []string{},
+		},
+	}
+}
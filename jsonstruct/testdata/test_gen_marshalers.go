@@ -0,0 +1,37 @@
+package test_package
+
+import (
+	"encoding/json"
+)
+
+type test_gen_marshalers struct {
+	Active  bool `json:"active,omitempty"`
+	Address struct {
+		City string `json:"city,omitempty"`
+		Geo  struct {
+			Lat float64 `json:"lat,omitempty"`
+		} `json:"geo,omitempty"`
+		Tags []string `json:"tags,omitempty"`
+	} `json:"address,omitempty"`
+	Codes []float64 `json:"codes"`
+	Items []struct {
+		ID float64 `json:"id,omitempty"`
+	} `json:"items"`
+	Name string  `json:"name,omitempty"`
+	Zeta float64 `json:"zeta,omitempty"`
+}
+
+// MarshalJSON encodes v with nil slice fields encoded as [] rather than null.
+func (v test_gen_marshalers) MarshalJSON() ([]byte, error) {
+	type alias test_gen_marshalers
+	a := alias(v)
+	if a.Codes == nil {
+		a.Codes = []float64{}
+	}
+	if a.Items == nil {
+		a.Items = []struct {
+			ID float64 `json:"id,omitempty"`
+		}{}
+	}
+	return json.Marshal(a)
+}
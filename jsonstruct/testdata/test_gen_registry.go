@@ -0,0 +1,80 @@
+package test_package
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+type test_gen_registry struct {
+	Events test_gen_registryEvents `json:"events,omitempty"`
+	ID     string                  `json:"id,omitempty"`
+	Tags   []struct {
+		Name string `json:"name,omitempty"`
+		Type string `json:"type,omitempty"`
+	} `json:"tags,omitempty"`
+}
+
+// test_gen_registryEventsItem is implemented by each variant of the elements of test_gen_registryEvents,
+// selected by their "type" field.
+type test_gen_registryEventsItem interface {
+	istest_gen_registryEventsItem()
+}
+
+type test_gen_registryEventsClick struct {
+	Button string  `json:"button,omitempty"`
+	Type   string  `json:"type,omitempty"`
+	X      float64 `json:"x,omitempty"`
+	Y      float64 `json:"y,omitempty"`
+}
+
+func (test_gen_registryEventsClick) istest_gen_registryEventsItem() {}
+
+type test_gen_registryEventsView struct {
+	Type string `json:"type,omitempty"`
+	URL  string `json:"url,omitempty"`
+}
+
+func (test_gen_registryEventsView) istest_gen_registryEventsItem() {}
+
+// test_gen_registryEvents holds elements of any test_gen_registryEventsItem variant.
+type test_gen_registryEvents []test_gen_registryEventsItem
+
+// UnmarshalJSON decodes each element into the test_gen_registryEventsItem variant named by its
+// "type" field.
+func (s *test_gen_registryEvents) UnmarshalJSON(data []byte) error {
+	var elems []json.RawMessage
+	if err := json.Unmarshal(data, &elems); err != nil {
+		return err
+	}
+	*s = make(test_gen_registryEvents, 0, len(elems))
+	for _, elem := range elems {
+		var d struct {
+			Value string `json:"type"`
+		}
+		if err := json.Unmarshal(elem, &d); err != nil {
+			return err
+		}
+		var item test_gen_registryEventsItem
+		switch d.Value {
+		case "click":
+			item = &test_gen_registryEventsClick{}
+		case "view":
+			item = &test_gen_registryEventsView{}
+		default:
+			return fmt.Errorf("unknown type %q", d.Value)
+		}
+		if err := json.Unmarshal(elem, item); err != nil {
+			return err
+		}
+		*s = append(*s, item)
+	}
+	return nil
+}
+
+// TypeRegistry maps the name of each generated type to its reflect.Type.
+var TypeRegistry = map[string]reflect.Type{
+	"test_gen_registry":            reflect.TypeOf((*test_gen_registry)(nil)).Elem(),
+	"test_gen_registryEventsClick": reflect.TypeOf((*test_gen_registryEventsClick)(nil)).Elem(),
+	"test_gen_registryEventsView":  reflect.TypeOf((*test_gen_registryEventsView)(nil)).Elem(),
+}
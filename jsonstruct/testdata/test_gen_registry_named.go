@@ -0,0 +1,46 @@
+package test_package
+
+import (
+	"reflect"
+)
+
+type test_gen_registry_named struct {
+	Address    test_gen_registry_namedAddress     `json:"address,omitempty"`
+	AddressGeo test_gen_registry_namedAddressGeo2 `json:"addressGeo,omitempty"`
+	ID         int64                              `json:"id,omitempty"`
+	Orders     []test_gen_registry_namedOrder     `json:"orders,omitempty"`
+}
+
+type test_gen_registry_namedAddress struct {
+	Geo    test_gen_registry_namedAddressGeo `json:"geo,omitempty"`
+	Street string                            `json:"street,omitempty"`
+}
+
+type test_gen_registry_namedAddressGeo struct {
+	Lat float64 `json:"lat,omitempty"`
+	Lng float64 `json:"lng,omitempty"`
+}
+
+type test_gen_registry_namedAddressGeo2 struct {
+	Source string `json:"source,omitempty"`
+}
+
+type test_gen_registry_namedOrder struct {
+	ID    int64                              `json:"id,omitempty"`
+	Lines []test_gen_registry_namedOrderLine `json:"lines,omitempty"`
+}
+
+type test_gen_registry_namedOrderLine struct {
+	Qty int64  `json:"qty,omitempty"`
+	Sku string `json:"sku,omitempty"`
+}
+
+// TypeRegistry maps the name of each generated type to its reflect.Type.
+var TypeRegistry = map[string]reflect.Type{
+	"test_gen_registry_named":            reflect.TypeOf((*test_gen_registry_named)(nil)).Elem(),
+	"test_gen_registry_namedAddress":     reflect.TypeOf((*test_gen_registry_namedAddress)(nil)).Elem(),
+	"test_gen_registry_namedAddressGeo":  reflect.TypeOf((*test_gen_registry_namedAddressGeo)(nil)).Elem(),
+	"test_gen_registry_namedAddressGeo2": reflect.TypeOf((*test_gen_registry_namedAddressGeo2)(nil)).Elem(),
+	"test_gen_registry_namedOrder":       reflect.TypeOf((*test_gen_registry_namedOrder)(nil)).Elem(),
+	"test_gen_registry_namedOrderLine":   reflect.TypeOf((*test_gen_registry_namedOrderLine)(nil)).Elem(),
+}
@@ -0,0 +1,20 @@
+package test_package
+
+type test_gen_reset struct {
+	Baz []float64         `json:"baz,omitempty"`
+	Foo test_gen_resetFoo `json:"foo,omitempty"`
+}
+
+type test_gen_resetFoo struct {
+	Bar float64 `json:"bar,omitempty"`
+}
+
+// Reset sets every field of test_gen_reset to its zero value.
+func (v *test_gen_reset) Reset() {
+	*v = test_gen_reset{}
+}
+
+// Reset sets every field of test_gen_resetFoo to its zero value.
+func (v *test_gen_resetFoo) Reset() {
+	*v = test_gen_resetFoo{}
+}
@@ -0,0 +1,12 @@
+package test_package
+
+type test_heterogeneous_array struct {
+	Events []struct {
+		Delta *float64 `json:"delta,omitempty"`
+		ID    int64    `json:"id,omitempty"`
+		Key   *string  `json:"key,omitempty"`
+		Kind  string   `json:"kind,omitempty"`
+		X     *int64   `json:"x,omitempty"`
+		Y     *int64   `json:"y,omitempty"`
+	} `json:"events,omitempty"`
+}
@@ -0,0 +1,7 @@
+package test_package
+
+type test_int_type struct {
+	Price float64   `json:"price,omitempty"`
+	Qty   int       `json:"qty,omitempty"`
+	Ratio []float64 `json:"ratio,omitempty"`
+}
@@ -0,0 +1,12 @@
+package test_package
+
+import (
+	"encoding/json"
+)
+
+type test_json_number_fields struct {
+	Amounts []json.Number `json:"amounts,omitempty"`
+	Count   float64       `json:"count,omitempty"`
+	ID      json.Number   `json:"id,omitempty"`
+	Name    string        `json:"name,omitempty"`
+}
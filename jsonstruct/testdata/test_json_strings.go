@@ -0,0 +1,62 @@
+package test_package
+
+import (
+	"encoding/json"
+)
+
+type test_json_strings struct {
+	ID      float64                  `json:"id,omitempty"`
+	Note    string                   `json:"note,omitempty"`
+	Payload test_json_stringsPayload `json:"payload,omitempty"`
+	Tags    test_json_stringsTags    `json:"tags,omitempty"`
+}
+
+// test_json_stringsPayload holds the JSON encoded in the "payload" string field.
+type test_json_stringsPayload struct {
+	A float64   `json:"a,omitempty"`
+	B string    `json:"b,omitempty"`
+	C []float64 `json:"c,omitempty"`
+}
+
+// UnmarshalJSON decodes the JSON string holding v.
+func (v *test_json_stringsPayload) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	type plain test_json_stringsPayload
+	return json.Unmarshal([]byte(s), (*plain)(v))
+}
+
+// MarshalJSON encodes v as a JSON string.
+func (v test_json_stringsPayload) MarshalJSON() ([]byte, error) {
+	type plain test_json_stringsPayload
+	data, err := json.Marshal(plain(v))
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(string(data))
+}
+
+// test_json_stringsTags holds the JSON encoded in the "tags" string field.
+type test_json_stringsTags []string
+
+// UnmarshalJSON decodes the JSON string holding v.
+func (v *test_json_stringsTags) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	type plain test_json_stringsTags
+	return json.Unmarshal([]byte(s), (*plain)(v))
+}
+
+// MarshalJSON encodes v as a JSON string.
+func (v test_json_stringsTags) MarshalJSON() ([]byte, error) {
+	type plain test_json_stringsTags
+	data, err := json.Marshal(plain(v))
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(string(data))
+}
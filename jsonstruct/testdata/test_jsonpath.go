@@ -0,0 +1,7 @@
+package test_package
+
+type test_jsonpath struct {
+	Draft bool    `json:"draft,omitempty"`
+	ID    float64 `json:"id,omitempty"`
+	Title string  `json:"title,omitempty"`
+}
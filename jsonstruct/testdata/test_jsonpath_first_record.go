@@ -0,0 +1,7 @@
+package test_package
+
+type test_jsonpath_first_record struct {
+	Z int64  `json:"z,omitempty"`
+	A string `json:"a,omitempty"`
+	M bool   `json:"m,omitempty"`
+}
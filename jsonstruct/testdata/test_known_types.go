@@ -0,0 +1,8 @@
+package test_package
+
+type test_known_types struct {
+	ID    float64     `json:"id,omitempty"`
+	Name  interface{} `json:"name,omitempty"`
+	Owner Account     `json:"owner,omitempty"`
+	Score interface{} `json:"score,omitempty"`
+}
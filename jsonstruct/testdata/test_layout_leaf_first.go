@@ -0,0 +1,72 @@
+package test_package
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// test_layout_leaf_firstEventsItem is implemented by each variant of the elements of test_layout_leaf_firstEvents,
+// selected by their "type" field.
+type test_layout_leaf_firstEventsItem interface {
+	istest_layout_leaf_firstEventsItem()
+}
+
+type test_layout_leaf_firstEventsClick struct {
+	Button string  `json:"button,omitempty"`
+	Type   string  `json:"type,omitempty"`
+	X      float64 `json:"x,omitempty"`
+	Y      float64 `json:"y,omitempty"`
+}
+
+func (test_layout_leaf_firstEventsClick) istest_layout_leaf_firstEventsItem() {}
+
+type test_layout_leaf_firstEventsView struct {
+	Type string `json:"type,omitempty"`
+	URL  string `json:"url,omitempty"`
+}
+
+func (test_layout_leaf_firstEventsView) istest_layout_leaf_firstEventsItem() {}
+
+// test_layout_leaf_firstEvents holds elements of any test_layout_leaf_firstEventsItem variant.
+type test_layout_leaf_firstEvents []test_layout_leaf_firstEventsItem
+
+// UnmarshalJSON decodes each element into the test_layout_leaf_firstEventsItem variant named by its
+// "type" field.
+func (s *test_layout_leaf_firstEvents) UnmarshalJSON(data []byte) error {
+	var elems []json.RawMessage
+	if err := json.Unmarshal(data, &elems); err != nil {
+		return err
+	}
+	*s = make(test_layout_leaf_firstEvents, 0, len(elems))
+	for _, elem := range elems {
+		var d struct {
+			Value string `json:"type"`
+		}
+		if err := json.Unmarshal(elem, &d); err != nil {
+			return err
+		}
+		var item test_layout_leaf_firstEventsItem
+		switch d.Value {
+		case "click":
+			item = &test_layout_leaf_firstEventsClick{}
+		case "view":
+			item = &test_layout_leaf_firstEventsView{}
+		default:
+			return fmt.Errorf("unknown type %q", d.Value)
+		}
+		if err := json.Unmarshal(elem, item); err != nil {
+			return err
+		}
+		*s = append(*s, item)
+	}
+	return nil
+}
+
+type test_layout_leaf_first struct {
+	Events test_layout_leaf_firstEvents `json:"events,omitempty"`
+	ID     string                       `json:"id,omitempty"`
+	Tags   []struct {
+		Name string `json:"name,omitempty"`
+		Type string `json:"type,omitempty"`
+	} `json:"tags,omitempty"`
+}
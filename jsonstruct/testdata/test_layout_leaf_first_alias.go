@@ -0,0 +1,16 @@
+package test_package
+
+type test_layout_leaf_first_aliasElement struct {
+	Bar float64 `json:"bar,omitempty"`
+	Baz struct {
+		Zap bool `json:"zap,omitempty"`
+	} `json:"baz,omitempty"`
+	Foo float64 `json:"foo,omitempty"`
+}
+
+// Newtest_layout_leaf_first_aliasElement returns a new test_layout_leaf_first_aliasElement with its slice fields initialized to empty slices.
+func Newtest_layout_leaf_first_aliasElement() *test_layout_leaf_first_aliasElement {
+	return &test_layout_leaf_first_aliasElement{}
+}
+
+type test_layout_leaf_first_alias []test_layout_leaf_first_aliasElement
@@ -0,0 +1,13 @@
+package test_package
+
+type test_max_depth struct {
+	Geometry struct {
+		Coordinates []float64 `json:"coordinates,omitempty"`
+		Type        string    `json:"type,omitempty"`
+	} `json:"geometry,omitempty"`
+	Properties struct {
+		Meta map[string]interface{} `json:"meta,omitempty"`
+		Name string                 `json:"name,omitempty"`
+	} `json:"properties,omitempty"`
+	Type string `json:"type,omitempty"`
+}
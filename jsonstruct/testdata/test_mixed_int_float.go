@@ -0,0 +1,7 @@
+package test_package
+
+type test_mixed_int_float struct {
+	Price float64   `json:"price,omitempty"`
+	Qty   int64     `json:"qty,omitempty"`
+	Ratio []float64 `json:"ratio,omitempty"`
+}
@@ -0,0 +1,7 @@
+package test_package
+
+type test_name_case_exported struct {
+	X1st string `json:"1st,omitempty"`
+	X_id int64  `json:"_id,omitempty"`
+	Ok   bool   `json:"ok,omitempty"`
+}
@@ -0,0 +1,8 @@
+package test_package
+
+type test_name_case_preserve struct {
+	HTTPStatus int64
+	FirstName  string `json:"firstName,omitempty"`
+	Is_admin   bool   `json:"is-admin,omitempty"`
+	Last_name  string `json:"last_name,omitempty"`
+}
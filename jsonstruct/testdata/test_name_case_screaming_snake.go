@@ -0,0 +1,8 @@
+package test_package
+
+type test_name_case_screaming_snake struct {
+	HTTP_STATUS int64  `json:"HTTPStatus,omitempty"`
+	FIRST_NAME  string `json:"firstName,omitempty"`
+	IS_ADMIN    bool   `json:"is-admin,omitempty"`
+	LAST_NAME   string `json:"last_name,omitempty"`
+}
@@ -0,0 +1,32 @@
+package test_package
+
+type test_named_nested struct {
+	Address    test_named_nestedAddress     `json:"address,omitempty"`
+	AddressGeo test_named_nestedAddressGeo2 `json:"addressGeo,omitempty"`
+	ID         int64                        `json:"id,omitempty"`
+	Orders     []test_named_nestedOrder     `json:"orders,omitempty"`
+}
+
+type test_named_nestedAddress struct {
+	Geo    test_named_nestedAddressGeo `json:"geo,omitempty"`
+	Street string                      `json:"street,omitempty"`
+}
+
+type test_named_nestedAddressGeo struct {
+	Lat float64 `json:"lat,omitempty"`
+	Lng float64 `json:"lng,omitempty"`
+}
+
+type test_named_nestedAddressGeo2 struct {
+	Source string `json:"source,omitempty"`
+}
+
+type test_named_nestedOrder struct {
+	ID    int64                        `json:"id,omitempty"`
+	Lines []test_named_nestedOrderLine `json:"lines,omitempty"`
+}
+
+type test_named_nestedOrderLine struct {
+	Qty int64  `json:"qty,omitempty"`
+	Sku string `json:"sku,omitempty"`
+}
@@ -0,0 +1,25 @@
+package test_package
+
+type test_named_nested_collision []test_named_nested_collisionElement
+
+type test_named_nested_collisionElement struct {
+	Element     test_named_nested_collisionElement2     `json:"element,omitempty"`
+	ElementKind test_named_nested_collisionElementKind2 `json:"elementKind,omitempty"`
+}
+
+type test_named_nested_collisionElement2 struct {
+	A    int64                                  `json:"a,omitempty"`
+	Kind test_named_nested_collisionElementKind `json:"kind,omitempty"`
+}
+
+type test_named_nested_collisionElementKind2 struct {
+	B int64 `json:"b,omitempty"`
+}
+
+// test_named_nested_collisionElementKind is the type of the "kind" field.
+type test_named_nested_collisionElementKind string
+
+// Values of test_named_nested_collisionElementKind observed in the input.
+const (
+	test_named_nested_collisionElementKindX test_named_nested_collisionElementKind = "x"
+)
@@ -0,0 +1,43 @@
+package test_package
+
+type test_named_nested_generic struct {
+	Meta   test_named_nested_genericMeta   `json:"meta,omitempty"`
+	Point  test_named_nested_genericPoint  `json:"point,omitempty"`
+	Series test_named_nested_genericSeries `json:"series,omitempty"`
+}
+
+type test_named_nested_genericMeta struct {
+	Data test_named_nested_genericMetaData `json:"data,omitempty"`
+}
+
+type test_named_nested_genericMetaData struct {
+	Value string `json:"value,omitempty"`
+}
+
+type test_named_nested_genericPoint struct {
+	X test_named_nested_genericPointX `json:"x,omitempty"`
+	Y test_named_nested_genericPointY `json:"y,omitempty"`
+}
+
+type test_named_nested_genericPointX struct {
+	Unit  string `json:"unit,omitempty"`
+	Value int64  `json:"value,omitempty"`
+}
+
+type test_named_nested_genericPointY struct {
+	Unit  string `json:"unit,omitempty"`
+	Value int64  `json:"value,omitempty"`
+}
+
+type test_named_nested_genericSeries struct {
+	Data []test_named_nested_genericSeriesData `json:"data,omitempty"`
+}
+
+type test_named_nested_genericSeriesData struct {
+	X int64                                `json:"x,omitempty"`
+	Y test_named_nested_genericSeriesDataY `json:"y,omitempty"`
+}
+
+type test_named_nested_genericSeriesDataY struct {
+	Value float64 `json:"value,omitempty"`
+}
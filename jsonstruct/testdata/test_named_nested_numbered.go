@@ -0,0 +1,23 @@
+package test_package
+
+type test_named_nested_numbered struct {
+	Field1 test_named_nested_numberedField1 `json:"$,omitempty"`
+	Field2 test_named_nested_numberedField2 `json:"1,omitempty"`
+	Ok     test_named_nested_numberedOk     `json:"ok,omitempty"`
+}
+
+type test_named_nested_numberedField1 struct {
+	B test_named_nested_numberedField1B `json:"b,omitempty"`
+}
+
+type test_named_nested_numberedField1B struct {
+	X int64 `json:"x,omitempty"`
+}
+
+type test_named_nested_numberedField2 struct {
+	A int64 `json:"a,omitempty"`
+}
+
+type test_named_nested_numberedOk struct {
+	C int64 `json:"c,omitempty"`
+}
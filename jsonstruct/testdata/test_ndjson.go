@@ -0,0 +1,8 @@
+package test_package
+
+type test_ndjson struct {
+	Active bool     `json:"active,omitempty"`
+	ID     int64    `json:"id,omitempty"`
+	Name   string   `json:"name,omitempty"`
+	Tags   []string `json:"tags,omitempty"`
+}
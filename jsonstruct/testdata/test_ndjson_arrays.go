@@ -0,0 +1,9 @@
+package test_package
+
+type test_ndjson_arrays struct {
+	Err   *string `json:"err,omitempty"`
+	Level string  `json:"level,omitempty"`
+	Ms    *int64  `json:"ms,omitempty"`
+	Msg   string  `json:"msg,omitempty"`
+	Ok    *bool   `json:"ok,omitempty"`
+}
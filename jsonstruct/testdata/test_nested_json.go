@@ -0,0 +1,8 @@
+package test_package
+
+type test_nested_json struct {
+	Baz []int64 `json:"baz,omitempty"`
+	Foo struct {
+		Bar int64 `json:"bar,omitempty"`
+	} `json:"foo,omitempty"`
+}
@@ -0,0 +1,14 @@
+package test_package
+
+type test_null_heavy struct {
+	// Null in 2 of 3 records (66.7%).
+	DeletedAt *string `json:"deleted_at,omitempty"`
+	ID        float64 `json:"id,omitempty"`
+	// Null in 2 of 3 records (66.7%).
+	Labels []string    `json:"labels,omitempty"`
+	Note   interface{} `json:"note,omitempty"`
+	// Null in 2 of 3 records (66.7%).
+	Parent *struct {
+		ID float64 `json:"id,omitempty"`
+	} `json:"parent,omitempty"`
+}
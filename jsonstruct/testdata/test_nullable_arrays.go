@@ -0,0 +1,12 @@
+package test_package
+
+type test_nullable_arrays struct {
+	Ids   []int64 `json:"ids,omitempty"`
+	Items []struct {
+		X int64 `json:"x,omitempty"`
+	} `json:"items,omitempty"`
+	Later []struct {
+		Y string `json:"y,omitempty"`
+	} `json:"later,omitempty"`
+	Tags []interface{} `json:"tags,omitempty"`
+}
@@ -0,0 +1,42 @@
+package test_package
+
+import (
+	"encoding/json"
+)
+
+type test_nullable_generic struct {
+	ID    float64          `json:"id,omitempty"`
+	Name  Nullable[string] `json:"name,omitempty"`
+	Owner struct {
+		Admin Nullable[bool] `json:"admin,omitempty"`
+		Login string         `json:"login,omitempty"`
+	} `json:"owner,omitempty"`
+	Score interface{} `json:"score,omitempty"`
+}
+
+// Nullable holds a JSON value that may be null or missing. Valid reports
+// whether a value was present. An invalid Nullable is encoded as null, so a
+// missing field round-trips as an explicit null.
+type Nullable[T any] struct {
+	Value T
+	Valid bool
+}
+
+func (n *Nullable[T]) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		*n = Nullable[T]{}
+		return nil
+	}
+	if err := json.Unmarshal(data, &n.Value); err != nil {
+		return err
+	}
+	n.Valid = true
+	return nil
+}
+
+func (n Nullable[T]) MarshalJSON() ([]byte, error) {
+	if !n.Valid {
+		return []byte("null"), nil
+	}
+	return json.Marshal(n.Value)
+}
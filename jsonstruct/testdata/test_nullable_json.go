@@ -2,6 +2,6 @@ package test_package
 
 type test_nullable_json struct {
 	Foo []struct {
-		Bar float64 `json:"bar,omitempty"`
+		Bar int64 `json:"bar,omitempty"`
 	} `json:"foo,omitempty"`
 }
@@ -0,0 +1,6 @@
+package test_package
+
+type test_nullable_mixed_numbers struct {
+	M *int64   `json:"m,omitempty"`
+	N *float64 `json:"n,omitempty"`
+}
@@ -0,0 +1,5 @@
+package test_package
+
+type test_nullable_null_parent struct {
+	O interface{} `json:"o,omitempty"`
+}
@@ -0,0 +1,11 @@
+package test_package
+
+type test_pointers_for_optional struct {
+	ID    int64   `json:"id,omitempty"`
+	Name  *string `json:"name,omitempty"`
+	Owner *struct {
+		Admin *bool  `json:"admin,omitempty"`
+		Login string `json:"login,omitempty"`
+	} `json:"owner,omitempty"`
+	Score interface{} `json:"score,omitempty"`
+}
@@ -0,0 +1,7 @@
+package test_package
+
+type test_pointers_null_parent struct {
+	O *struct {
+		X int64 `json:"x"`
+	} `json:"o,omitempty"`
+}
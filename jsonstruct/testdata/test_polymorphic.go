@@ -0,0 +1,72 @@
+package test_package
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+type test_polymorphic struct {
+	Events test_polymorphicEvents `json:"events,omitempty"`
+	ID     string                 `json:"id,omitempty"`
+	Tags   []struct {
+		Name string `json:"name,omitempty"`
+		Type string `json:"type,omitempty"`
+	} `json:"tags,omitempty"`
+}
+
+// test_polymorphicEventsItem is implemented by each variant of the elements of test_polymorphicEvents,
+// selected by their "type" field.
+type test_polymorphicEventsItem interface {
+	istest_polymorphicEventsItem()
+}
+
+type test_polymorphicEventsClick struct {
+	Button string  `json:"button,omitempty"`
+	Type   string  `json:"type,omitempty"`
+	X      float64 `json:"x,omitempty"`
+	Y      float64 `json:"y,omitempty"`
+}
+
+func (test_polymorphicEventsClick) istest_polymorphicEventsItem() {}
+
+type test_polymorphicEventsView struct {
+	Type string `json:"type,omitempty"`
+	URL  string `json:"url,omitempty"`
+}
+
+func (test_polymorphicEventsView) istest_polymorphicEventsItem() {}
+
+// test_polymorphicEvents holds elements of any test_polymorphicEventsItem variant.
+type test_polymorphicEvents []test_polymorphicEventsItem
+
+// UnmarshalJSON decodes each element into the test_polymorphicEventsItem variant named by its
+// "type" field.
+func (s *test_polymorphicEvents) UnmarshalJSON(data []byte) error {
+	var elems []json.RawMessage
+	if err := json.Unmarshal(data, &elems); err != nil {
+		return err
+	}
+	*s = make(test_polymorphicEvents, 0, len(elems))
+	for _, elem := range elems {
+		var d struct {
+			Value string `json:"type"`
+		}
+		if err := json.Unmarshal(elem, &d); err != nil {
+			return err
+		}
+		var item test_polymorphicEventsItem
+		switch d.Value {
+		case "click":
+			item = &test_polymorphicEventsClick{}
+		case "view":
+			item = &test_polymorphicEventsView{}
+		default:
+			return fmt.Errorf("unknown type %q", d.Value)
+		}
+		if err := json.Unmarshal(elem, item); err != nil {
+			return err
+		}
+		*s = append(*s, item)
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package test_package
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+type test_polymorphic_collision struct {
+	Events test_polymorphic_collisionEvents `json:"events,omitempty"`
+}
+
+// test_polymorphic_collisionEventsItem is implemented by each variant of the elements of test_polymorphic_collisionEvents,
+// selected by their "type" field.
+type test_polymorphic_collisionEventsItem interface {
+	istest_polymorphic_collisionEventsItem()
+}
+
+type test_polymorphic_collisionEvents2 struct {
+	Note string `json:"note,omitempty"`
+	Type string `json:"type,omitempty"`
+}
+
+func (test_polymorphic_collisionEvents2) istest_polymorphic_collisionEventsItem() {}
+
+type test_polymorphic_collisionEventsClick struct {
+	Type string `json:"type,omitempty"`
+	X    int64  `json:"x,omitempty"`
+}
+
+func (test_polymorphic_collisionEventsClick) istest_polymorphic_collisionEventsItem() {}
+
+type test_polymorphic_collisionEventsItem2 struct {
+	Sku  string `json:"sku,omitempty"`
+	Type string `json:"type,omitempty"`
+}
+
+func (test_polymorphic_collisionEventsItem2) istest_polymorphic_collisionEventsItem() {}
+
+// test_polymorphic_collisionEvents holds elements of any test_polymorphic_collisionEventsItem variant.
+type test_polymorphic_collisionEvents []test_polymorphic_collisionEventsItem
+
+// UnmarshalJSON decodes each element into the test_polymorphic_collisionEventsItem variant named by its
+// "type" field.
+func (s *test_polymorphic_collisionEvents) UnmarshalJSON(data []byte) error {
+	var elems []json.RawMessage
+	if err := json.Unmarshal(data, &elems); err != nil {
+		return err
+	}
+	*s = make(test_polymorphic_collisionEvents, 0, len(elems))
+	for _, elem := range elems {
+		var d struct {
+			Value string `json:"type"`
+		}
+		if err := json.Unmarshal(elem, &d); err != nil {
+			return err
+		}
+		var item test_polymorphic_collisionEventsItem
+		switch d.Value {
+		case "":
+			item = &test_polymorphic_collisionEvents2{}
+		case "click":
+			item = &test_polymorphic_collisionEventsClick{}
+		case "item":
+			item = &test_polymorphic_collisionEventsItem2{}
+		default:
+			return fmt.Errorf("unknown type %q", d.Value)
+		}
+		if err := json.Unmarshal(elem, item); err != nil {
+			return err
+		}
+		*s = append(*s, item)
+	}
+	return nil
+}
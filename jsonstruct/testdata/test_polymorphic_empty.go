@@ -0,0 +1,65 @@
+package test_package
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+type test_polymorphic_empty struct {
+	Items test_polymorphic_emptyItems `json:"items,omitempty"`
+}
+
+// test_polymorphic_emptyItemsItem is implemented by each variant of the elements of test_polymorphic_emptyItems,
+// selected by their "type" field.
+type test_polymorphic_emptyItemsItem interface {
+	istest_polymorphic_emptyItemsItem()
+}
+
+type test_polymorphic_emptyItemsX struct {
+	A    int64  `json:"a,omitempty"`
+	Type string `json:"type,omitempty"`
+}
+
+func (test_polymorphic_emptyItemsX) istest_polymorphic_emptyItemsItem() {}
+
+type test_polymorphic_emptyItemsY struct {
+	B    string `json:"b,omitempty"`
+	Type string `json:"type,omitempty"`
+}
+
+func (test_polymorphic_emptyItemsY) istest_polymorphic_emptyItemsItem() {}
+
+// test_polymorphic_emptyItems holds elements of any test_polymorphic_emptyItemsItem variant.
+type test_polymorphic_emptyItems []test_polymorphic_emptyItemsItem
+
+// UnmarshalJSON decodes each element into the test_polymorphic_emptyItemsItem variant named by its
+// "type" field.
+func (s *test_polymorphic_emptyItems) UnmarshalJSON(data []byte) error {
+	var elems []json.RawMessage
+	if err := json.Unmarshal(data, &elems); err != nil {
+		return err
+	}
+	*s = make(test_polymorphic_emptyItems, 0, len(elems))
+	for _, elem := range elems {
+		var d struct {
+			Value string `json:"type"`
+		}
+		if err := json.Unmarshal(elem, &d); err != nil {
+			return err
+		}
+		var item test_polymorphic_emptyItemsItem
+		switch d.Value {
+		case "x":
+			item = &test_polymorphic_emptyItemsX{}
+		case "y":
+			item = &test_polymorphic_emptyItemsY{}
+		default:
+			return fmt.Errorf("unknown type %q", d.Value)
+		}
+		if err := json.Unmarshal(elem, item); err != nil {
+			return err
+		}
+		*s = append(*s, item)
+	}
+	return nil
+}
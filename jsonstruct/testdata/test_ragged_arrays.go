@@ -0,0 +1,9 @@
+package test_package
+
+type test_ragged_arrays struct {
+	Empty   [][]string      `json:"empty,omitempty"`
+	Matrix  [][]int64       `json:"matrix,omitempty"`
+	Mixed   []interface{}   `json:"mixed,omitempty"`
+	Partial [][]float64     `json:"partial,omitempty"`
+	Ragged  [][]interface{} `json:"ragged,omitempty"`
+}
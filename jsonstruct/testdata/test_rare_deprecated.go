@@ -0,0 +1,10 @@
+package test_package
+
+type test_rare_deprecated struct {
+	// Deprecated: seen in only 1 of 4 records (25.0%)
+	Bar float64 `json:"bar,omitempty"`
+	Baz struct {
+		Zap bool `json:"zap,omitempty"`
+	} `json:"baz,omitempty"`
+	Foo float64 `json:"foo,omitempty"`
+}
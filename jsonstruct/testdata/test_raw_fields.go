@@ -0,0 +1,16 @@
+package test_package
+
+import (
+	"encoding/json"
+)
+
+type test_raw_fields struct {
+	ID       float64           `json:"id,omitempty"`
+	Metadata json.RawMessage   `json:"metadata,omitempty"`
+	Mixed    []json.RawMessage `json:"mixed,omitempty"`
+	Nested   struct {
+		Payload json.RawMessage `json:"payload,omitempty"`
+	} `json:"nested,omitempty"`
+	Payload json.RawMessage `json:"payload,omitempty"`
+	Value   json.RawMessage `json:"value,omitempty"`
+}
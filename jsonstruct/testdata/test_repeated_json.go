@@ -0,0 +1,9 @@
+package test_package
+
+type test_repeated_json struct {
+	Bar int64 `json:"bar,omitempty"`
+	Baz struct {
+		Zap bool `json:"zap,omitempty"`
+	} `json:"baz,omitempty"`
+	Foo int64 `json:"foo,omitempty"`
+}
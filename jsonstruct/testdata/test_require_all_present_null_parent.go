@@ -0,0 +1,5 @@
+package test_package
+
+type test_require_all_present_null_parent struct {
+	O interface{} `json:"o,omitempty"`
+}
@@ -1,9 +1,11 @@
 package test_package
 
-type test_repeated_json struct {
-	Foo float64 `json:"foo,omitempty"`
+type test_root_alias []test_root_aliasElement
+
+type test_root_aliasElement struct {
 	Bar float64 `json:"bar,omitempty"`
 	Baz struct {
 		Zap bool `json:"zap,omitempty"`
 	} `json:"baz,omitempty"`
+	Foo float64 `json:"foo,omitempty"`
 }
@@ -0,0 +1,3 @@
+package test_package
+
+type test_root_alias_scalars []string
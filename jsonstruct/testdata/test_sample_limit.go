@@ -0,0 +1,11 @@
+package test_package
+
+type test_sample_limit struct {
+	ID    int64       `json:"id,omitempty"`
+	Name  interface{} `json:"name,omitempty"`
+	Owner struct {
+		Admin bool   `json:"admin,omitempty"`
+		Login string `json:"login,omitempty"`
+	} `json:"owner,omitempty"`
+	Score interface{} `json:"score,omitempty"`
+}
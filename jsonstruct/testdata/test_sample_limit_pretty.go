@@ -0,0 +1,12 @@
+package test_package
+
+type test_sample_limit_pretty struct {
+	ID    int64 `json:"id,omitempty"`
+	Owner struct {
+		Login string `json:"login,omitempty"`
+	} `json:"owner,omitempty"`
+	Score float64 `json:"score,omitempty"`
+	Tags  []struct {
+		Name string `json:"name,omitempty"`
+	} `json:"tags,omitempty"`
+}
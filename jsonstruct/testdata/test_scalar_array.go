@@ -0,0 +1,8 @@
+package test_package
+
+type test_scalar_array struct {
+	Ids   interface{} `json:"ids,omitempty"`
+	Mixed interface{} `json:"mixed,omitempty"`
+	Owner interface{} `json:"owner,omitempty"`
+	Tag   interface{} `json:"tag,omitempty"`
+}
@@ -0,0 +1,11 @@
+package test_package
+
+type test_scalar_array_widen struct {
+	Ids   []float64   `json:"ids,omitempty"`
+	Mixed interface{} `json:"mixed,omitempty"`
+	Owner []struct {
+		Admin bool   `json:"admin,omitempty"`
+		Login string `json:"login,omitempty"`
+	} `json:"owner,omitempty"`
+	Tag []string `json:"tag,omitempty"`
+}
@@ -1,5 +1,5 @@
 package test_package
 
 type test_simple_json struct {
-	F_O_O float64 `json:"f.o-o,omitempty"`
+	F_O_O int64 `json:"f.o-o,omitempty"`
 }
@@ -0,0 +1,12 @@
+package test_package
+
+type test_smart_omitempty struct {
+	Events []struct {
+		Delta float64 `json:"delta,omitempty"`
+		ID    int64   `json:"id"`
+		Key   string  `json:"key,omitempty"`
+		Kind  string  `json:"kind"`
+		X     int64   `json:"x,omitempty"`
+		Y     int64   `json:"y,omitempty"`
+	} `json:"events"`
+}
@@ -0,0 +1,7 @@
+package test_package
+
+// test_struct_comment is a user.
+// It is generated.
+type test_struct_comment struct {
+	F_O_O float64 `json:"f.o-o,omitempty"`
+}
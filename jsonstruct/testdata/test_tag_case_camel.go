@@ -0,0 +1,8 @@
+package test_package
+
+type test_tag_case_camel struct {
+	HTTPStatus float64 `json:"httpStatus,omitempty"`
+	FirstName  string  `json:"firstName,omitempty"`
+	Is_Admin   bool    `json:"isAdmin,omitempty"`
+	LastName   string  `json:"lastName,omitempty"`
+}
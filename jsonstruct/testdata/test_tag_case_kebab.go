@@ -0,0 +1,8 @@
+package test_package
+
+type test_tag_case_kebab struct {
+	HTTPStatus float64 `json:"http-status,omitempty"`
+	FirstName  string  `json:"first-name,omitempty"`
+	Is_Admin   bool    `json:"is-admin,omitempty"`
+	LastName   string  `json:"last-name,omitempty"`
+}
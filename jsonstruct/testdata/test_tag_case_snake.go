@@ -0,0 +1,8 @@
+package test_package
+
+type test_tag_case_snake struct {
+	HTTPStatus float64 `json:"http_status,omitempty"`
+	FirstName  string  `json:"first_name,omitempty"`
+	Is_Admin   bool    `json:"is_admin,omitempty"`
+	LastName   string  `json:"last_name,omitempty"`
+}
@@ -0,0 +1,9 @@
+package test_package
+
+type test_tag_quote_double struct {
+	Back_Slash   float64 "json:\"back\\\\slash,omitempty\""
+	Back_Tick    float64 "json:\"back`tick,omitempty\""
+	Double_Quote float64 "json:\"double\\\"quote,omitempty\""
+	End__Comment float64 "json:\"end*/comment,omitempty\""
+	New_Line     float64 "json:\"new\\nline,omitempty\""
+}
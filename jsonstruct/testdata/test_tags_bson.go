@@ -0,0 +1,7 @@
+package test_package
+
+type test_tags_bson struct {
+	ID        string `bson:"_id,omitempty" json:"_id,omitempty"`
+	CreatedAt int64  `bson:"created_at,omitempty" json:"created_at,omitempty"`
+	UserName  string `bson:"userName,omitempty" json:"userName,omitempty"`
+}
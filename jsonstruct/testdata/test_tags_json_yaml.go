@@ -0,0 +1,8 @@
+package test_package
+
+type test_tags_json_yaml struct {
+	HTTPStatus float64 `json:"HTTPStatus,omitempty" yaml:"httpstatus,omitempty"`
+	FirstName  string  `json:"firstName,omitempty" yaml:"firstname,omitempty"`
+	Is_Admin   bool    `json:"is-admin,omitempty" yaml:"is-admin,omitempty"`
+	LastName   string  `json:"last_name,omitempty" yaml:"last_name,omitempty"`
+}
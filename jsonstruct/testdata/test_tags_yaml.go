@@ -0,0 +1,8 @@
+package test_package
+
+type test_tags_yaml struct {
+	HTTPStatus float64 `yaml:"httpstatus,omitempty"`
+	FirstName  string  `yaml:"firstname,omitempty"`
+	Is_Admin   bool    `yaml:"is-admin,omitempty"`
+	LastName   string  `yaml:"last_name,omitempty"`
+}
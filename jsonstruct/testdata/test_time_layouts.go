@@ -0,0 +1,16 @@
+package test_package
+
+import (
+	"time"
+)
+
+type test_time_layouts struct {
+	Count     float64   `json:"count,omitempty"`
+	CreatedAt time.Time `json:"created_at,omitempty"`
+	// Time with layout "2006/01/02 15:04"; use time.Parse to convert.
+	Logged string `json:"logged,omitempty"`
+	Mixed  string `json:"mixed,omitempty"`
+	Name   string `json:"name,omitempty"`
+	// Unix time in seconds; use time.Unix to convert.
+	Updated float64 `json:"updated,omitempty"`
+}
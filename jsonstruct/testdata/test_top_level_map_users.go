@@ -0,0 +1,9 @@
+package test_package
+
+type test_top_level_map_users map[string]test_top_level_map_user
+
+type test_top_level_map_user struct {
+	Admin bool   `json:"admin,omitempty"`
+	Email string `json:"email,omitempty"`
+	Name  string `json:"name,omitempty"`
+}
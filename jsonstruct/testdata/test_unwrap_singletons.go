@@ -0,0 +1,12 @@
+package test_package
+
+type test_unwrap_singletons struct {
+	Author struct {
+		Email string `json:"email,omitempty"`
+		Name  string `json:"name,omitempty"`
+	} `json:"author,omitempty"`
+	Ids   []int64  `json:"ids,omitempty"`
+	Note  string   `json:"note,omitempty"`
+	Tags  []string `json:"tags,omitempty"`
+	Title string   `json:"title,omitempty"`
+}
@@ -0,0 +1,12 @@
+package test_package
+
+import (
+	"encoding/json"
+)
+
+type test_use_json_number struct {
+	Amounts []json.Number `json:"amounts,omitempty"`
+	Count   json.Number   `json:"count,omitempty"`
+	ID      json.Number   `json:"id,omitempty"`
+	Name    string        `json:"name,omitempty"`
+}
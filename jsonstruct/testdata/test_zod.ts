@@ -0,0 +1,13 @@
+import { z } from "zod";
+
+export const test_zod = z.object({
+  id: z.number(),
+  name: z.string().nullable().optional(),
+  owner: z.object({
+    admin: z.boolean().optional(),
+    login: z.string(),
+  }).optional(),
+  score: z.unknown(),
+});
+
+export type test_zod = z.infer<typeof test_zod>;
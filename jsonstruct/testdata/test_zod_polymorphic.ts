@@ -0,0 +1,23 @@
+import { z } from "zod";
+
+export const test_zod_polymorphic = z.object({
+  events: z.array(z.discriminatedUnion("type", [
+    z.object({
+      button: z.string().optional(),
+      type: z.string(),
+      x: z.number(),
+      y: z.number(),
+    }),
+    z.object({
+      type: z.string(),
+      url: z.string(),
+    }),
+  ])),
+  id: z.string(),
+  tags: z.array(z.object({
+    name: z.string(),
+    type: z.string(),
+  })),
+});
+
+export type test_zod_polymorphic = z.infer<typeof test_zod_polymorphic>;
@@ -0,0 +1,513 @@
+package jsonstruct
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// stringTypes are the types that may be generated for JSON strings. Differing
+// string types widen to string when merged.
+var stringTypes = map[string]bool{
+	"string":    true,
+	"net.IP":    true,
+	"time.Time": true,
+	"[]byte":    true,
+}
+
+// numericTypes are the types that may be generated for JSON numbers.
+// Differing numeric types widen to float64 when merged.
+var numericTypes = map[string]bool{
+	"float64": true,
+	"int64":   true,
+	"int":     true,
+}
+
+type Fields []*Type
+
+func (f Fields) String() string {
+	result := []string{}
+	for _, field := range f {
+		result = append(result, field.String())
+	}
+	return strings.Join(result, "\n")
+}
+
+type Type struct {
+	Name     string
+	// Repeated is the number of slice dimensions of the type.
+	Repeated int
+	Type     string
+	Tags     map[string]string
+	Children Fields
+	Config   *Config
+	// Key is the JSON key of the field.
+	Key string
+	// Keys holds each spelling of the JSON key that was merged into the
+	// field, with the number of times it was seen.
+	Keys map[string]int
+	// Count is the number of times the value was observed.
+	Count int
+	// Elems is the number of array elements merged into the element type of
+	// an array.
+	Elems int
+	// Observed holds each type the value was observed as, with the number of
+	// times it was seen. JSON nulls are recorded as "null".
+	Observed map[string]int
+	// Comment, if set, is emitted as a doc comment above the field.
+	Comment string
+	// KeepEmpty, if true, drops omitempty from the field's json tag so that
+	// empty values are always encoded.
+	KeepEmpty bool
+	// Layout is the time layout every value matched, if any. Unix
+	// timestamps have the layout "unix seconds" or "unix millis", and strings
+	// holding embedded JSON have the layout "json".
+	Layout string
+	// Variants holds a type per discriminator value for arrays of objects
+	// with a polymorphic discriminator field. Each variant's Key is its
+	// discriminator value.
+	Variants []*Type
+	// Value is the Go literal of the scalar value every observation held,
+	// or empty if the observed values differed. It's only recorded when
+	// detecting constants.
+	Value string
+	// Example is the first scalar value observed, recorded only when
+	// generating an example document or example comments.
+	Example interface{}
+	// Pointer, if true, emits the type as a pointer.
+	Pointer bool
+	// Numbers holds the numeric values observed, recorded only when writing
+	// histograms. Past Config.NumberSample values it holds a random sample
+	// of them.
+	Numbers []float64
+	// NumberCount is the number of numeric values observed.
+	NumberCount int
+	// Singleton is true if every array the value was observed as held
+	// exactly one element.
+	Singleton bool
+	// NonNumeric is true if a string that doesn't hold a number was
+	// observed, recorded only when generating Flexible types.
+	NonNumeric bool
+	// Values holds the distinct string and integer values observed, in the
+	// order they were first seen, recorded only when detecting enums. It
+	// holds at most Config.Enums values.
+	Values []string
+	// Map, if true, emits the type as a map from string keys to values of
+	// the type. The fields of a map of structs are the union of the fields
+	// of its values.
+	Map bool
+}
+
+func (t *Type) GetType() string {
+	typ := strings.Repeat("[]", t.Repeated) + t.Type
+	if t.Map {
+		typ = strings.Repeat("[]", t.Repeated) + "map[string]" + t.Type
+	}
+	if t.Pointer {
+		return "*" + typ
+	}
+	return typ
+}
+
+// splitObserved splits a type recorded in Observed, such as "[][]int64",
+// into its element type and number of slice dimensions. The brackets of a
+// []byte element type are kept.
+func splitObserved(observed string) (string, int) {
+	base := strings.TrimLeft(observed, "[]")
+	if base == "byte" {
+		base = "[]byte"
+	}
+	return base, (len(observed) - len(base)) / 2
+}
+
+// nonNullTypes returns the sorted types other than null that t was observed
+// as. Differing numeric types at the same array depth are collapsed to
+// float64, and differing string types to string, as Merge widens them.
+func (t *Type) nonNullTypes() []string {
+	widened := map[string]bool{}
+	for observed := range t.Observed {
+		if observed == "null" {
+			continue
+		}
+		base, repeated := splitObserved(observed)
+		prefix := strings.Repeat("[]", repeated)
+		switch {
+		case numericTypes[base] && t.observedOther(prefix, base, numericTypes):
+			base = "float64"
+		case stringTypes[base] && t.observedOther(prefix, base, stringTypes):
+			base = "string"
+		}
+		widened[prefix+base] = true
+	}
+	result := make([]string, 0, len(widened))
+	for typ := range widened {
+		result = append(result, typ)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// observedOther reports whether t was observed as a type of kind other than
+// base at the array depth of prefix.
+func (t *Type) observedOther(prefix, base string, kind map[string]bool) bool {
+	for other := range kind {
+		if other != base && t.Observed[prefix+other] > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// objects returns the number of objects the fields of t could have appeared
+// in: the number of elements for arrays and the number of non-null values
+// otherwise.
+func (t *Type) objects() int {
+	if t.Repeated > 0 || t.Map {
+		return t.Elems
+	}
+	return t.Count - t.Observed["null"]
+}
+
+// emptyArray reports whether t is an array that never held an element, such
+// as [] or [[], []].
+func (t *Type) emptyArray() bool {
+	return t.Repeated > 0 && t.Elems == 0
+}
+
+// omittable reports whether values of t can be omitted by omitempty: those
+// of pointer, slice, map, string and interface types. Zero structs are never
+// omitted, and omitting zero numbers and bools loses them.
+func (t *Type) omittable() bool {
+	if t.Pointer || t.Repeated > 0 || t.Map || strings.HasPrefix(t.Type, "map[") || strings.HasPrefix(t.Type, "[]") {
+		return true
+	}
+	switch t.Type {
+	case "string", "interface{}", "json.RawMessage", "json.Number", "net.IP":
+		return true
+	}
+	return false
+}
+
+// nullOnly reports whether t was only ever observed as null.
+func (t *Type) nullOnly() bool {
+	return len(t.Observed) == 1 && t.Observed["null"] > 0
+}
+
+func (t *Type) GetTags() string {
+	if len(t.Tags) == 0 {
+		return ""
+	}
+
+	keys := make([]string, 0, len(t.Tags))
+	for key := range t.Tags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	parts := []string{}
+	for _, k := range keys {
+		v := t.Tags[k]
+		if t.Config.OmitEmpty && !t.KeepEmpty && (!t.Config.HonestOmitEmpty || t.omittable()) {
+			v += ",omitempty"
+		}
+		parts = append(parts, k+":"+strconv.Quote(v))
+	}
+	tags := strings.Join(parts, " ")
+	if t.Config.TagQuote == "double" || strings.Contains(tags, "`") {
+		// a raw string literal can't hold a backtick.
+		return strconv.Quote(tags)
+	}
+	return "`" + tags + "`"
+}
+
+func (t *Type) String() string {
+	return fmt.Sprintf("%v%v %v %v%v", t.GetComment(), t.Name, t.GetTypeLiteral(), t.GetTags(), t.exampleComment())
+}
+
+// maxExampleLen is the length past which example comments are truncated.
+const maxExampleLen = 40
+
+// exampleComment returns a trailing comment holding the example value of the
+// scalar field t, or an empty string if examples aren't commented or t has
+// none.
+func (t *Type) exampleComment() string {
+	if !t.Config.ExampleComments || t.Key == "" || t.Repeated > 0 || t.Example == nil {
+		return ""
+	}
+	data, err := json.Marshal(t.Example)
+	if err != nil {
+		return ""
+	}
+	example := []rune(string(data))
+	if len(example) > maxExampleLen {
+		example = append(example[:maxExampleLen], []rune("...")...)
+	}
+	return " // e.g. " + sanitizeComment(string(example))
+}
+
+// GetTypeLiteral returns the Go type of t, spelling out the fields of struct
+// types.
+func (t *Type) GetTypeLiteral() string {
+	if t.Type == "struct" {
+		return fmt.Sprintf(`%v {
+%s
+}`, t.GetType(), t.Children)
+	}
+	return t.GetType()
+}
+
+// addComment appends line to t.Comment.
+func (t *Type) addComment(line string) {
+	if t.Comment != "" {
+		t.Comment += "\n"
+	}
+	t.Comment += line
+}
+
+// GetComment returns t.Comment formatted as a line comment block, or an empty
+// string if t has no comment.
+func (t *Type) GetComment() string {
+	if t.Comment == "" {
+		return ""
+	}
+	var b strings.Builder
+	for _, line := range strings.Split(t.Comment, "\n") {
+		fmt.Fprintf(&b, "// %s\n", sanitizeComment(line))
+	}
+	return b.String()
+}
+
+// sanitizeComment makes s safe to embed in a line or block comment by
+// dropping control characters and breaking up "*/".
+func sanitizeComment(s string) string {
+	s = strings.Map(func(r rune) rune {
+		if r == '\t' {
+			return ' '
+		}
+		if unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, s)
+	return strings.Replace(s, "*/", "* /", -1)
+}
+
+func (t *Type) Merge(t2 *Type) error {
+	tNull, t2Null := t.nullOnly(), t2.nullOnly()
+	if tNull && !t2Null {
+		// keep the shape of the first non-null value.
+		t.Repeated, t.Children = t2.Repeated, t2.Children
+	}
+	t.Singleton = (t.Singleton || tNull) && (t2.Singleton || t2Null)
+	tEmpty, t2Empty := t.emptyArray(), t2.emptyArray()
+	if tEmpty && !t2Empty && t2.Repeated > 0 {
+		// an empty array says nothing about the element type.
+		t.Type, t.Repeated, t.Children, t.Layout = t2.Type, t2.Repeated, t2.Children, t2.Layout
+	}
+	t.Count += t2.Count
+	t.Elems += t2.Elems
+	for key, n := range t2.Keys {
+		if t.Keys == nil {
+			t.Keys = map[string]int{}
+		}
+		t.Keys[key] += n
+	}
+	for typ, n := range t2.Observed {
+		if t.Observed == nil {
+			t.Observed = map[string]int{}
+		}
+		t.Observed[typ] += n
+	}
+	switch {
+	case t2Null || t2Empty:
+		// null and [] say nothing about the variants.
+	case tNull || tEmpty:
+		t.Variants = t2.Variants
+	default:
+		if err := t.mergeVariants(t2); err != nil {
+			return err
+		}
+	}
+	if t.Value != t2.Value {
+		t.Value = ""
+	}
+	if t.Example == nil {
+		t.Example = t2.Example
+	}
+	t.mergeNumbers(t2)
+	t.NonNumeric = t.NonNumeric || t2.NonNumeric
+	for _, v := range t2.Values {
+		if len(t.Values) < t.Config.Enums && !containsKey(t.Values, v) {
+			t.Values = append(t.Values, v)
+		}
+	}
+	if (t2Empty || t2Null) && t.Repeated > 0 {
+		// a nil slice already represents null.
+		return nil
+	}
+	if tNull && t2.Repeated > 0 {
+		t.Type, t.Layout = t2.Type, t2.Layout
+		return nil
+	}
+	if (t.Layout == "json") != (t2.Layout == "json") {
+		// only some of the values held embedded JSON.
+		t.Type, t.Repeated, t.Children, t.Layout = "string", 0, nil, ""
+		return nil
+	}
+	switch {
+	case tNull || t2Null:
+		// a null says nothing about the layout of the other values.
+		if tNull {
+			t.Layout = t2.Layout
+		}
+	case t.Layout != t2.Layout:
+		t.Layout = ""
+		if t.Type == "time.Time" {
+			// the values don't share a layout.
+			t.Type = "string"
+		}
+	}
+	if t.Repeated > 0 && t2.Repeated > 0 && t.Repeated != t2.Repeated {
+		// ragged arrays, such as [[1], [[2]]], share the shallower depth and
+		// widen the element type below it.
+		if t2.Repeated < t.Repeated {
+			t.Repeated = t2.Repeated
+		}
+		t.Type, t.Children, t.Layout = t.Config.conflictType(), nil, ""
+		return nil
+	}
+	if t.Repeated != t2.Repeated && !t2Null {
+		// the values differ in array depth, such as "x" and ["x", "y"].
+		sameElem := t.Type == t2.Type || (stringTypes[t.Type] && stringTypes[t2.Type])
+		if t.Config.ScalarArray != "widen" || !sameElem || (t.Repeated != 0 && t2.Repeated != 0) {
+			t.Type, t.Repeated, t.Children = t.Config.conflictType(), 0, nil
+			return nil
+		}
+		if t2.Repeated > t.Repeated {
+			t.Repeated = t2.Repeated
+		}
+	}
+	if t.Type != t2.Type {
+		if stringTypes[t.Type] && stringTypes[t2.Type] {
+			t.Type = "string"
+			return nil
+		}
+		if numericTypes[t.Type] && numericTypes[t2.Type] {
+			// any fractional value makes the field float64.
+			t.Type = "float64"
+			return nil
+		}
+		t.Type = t.Config.conflictType()
+		return nil
+	}
+
+	fields := map[string]*Type{}
+	for _, typ := range t.Children {
+		fields[typ.mergeKey()] = typ
+	}
+	for _, typ := range t2.Children {
+		field, ok := fields[typ.mergeKey()]
+		if !ok {
+			t.Children = append(t.Children, typ)
+			continue
+		}
+		if err := field.Merge(typ); err != nil {
+			return fmt.Errorf("issue with '%v': %w", field.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// maxNumbers is the number of numeric values kept for histograms, beyond
+// which a sample of them is kept instead, unless Config.NumberSample is set.
+const maxNumbers = 10000
+
+// mergeNumbers adds the numeric values of t2 to those of t, keeping at most
+// Config.NumberSample of them by reservoir sampling. A value from a sample of t2
+// stands for as many values as t2 observed per sampled value.
+func (t *Type) mergeNumbers(t2 *Type) {
+	total := t.NumberCount + t2.NumberCount
+	limit := t.Config.numberSample()
+	step := 1
+	if len(t2.Numbers) > 0 && t2.NumberCount > len(t2.Numbers) {
+		step = t2.NumberCount / len(t2.Numbers)
+	}
+	for _, v := range t2.Numbers {
+		t.NumberCount += step
+		if len(t.Numbers) < limit {
+			t.Numbers = append(t.Numbers, v)
+		} else if i := sampleIndex(t.NumberCount); i < limit {
+			t.Numbers[i] = v
+		}
+	}
+	t.NumberCount = total
+}
+
+// sampleIndex returns a pseudo-random index below n derived from n alone,
+// so that sampling doesn't make output vary between runs.
+func sampleIndex(n int) int {
+	// splitmix64
+	x := uint64(n) + 0x9e3779b97f4a7c15
+	x = (x ^ x>>30) * 0xbf58476d1ce4e5b9
+	x = (x ^ x>>27) * 0x94d049bb133111eb
+	x ^= x >> 31
+	return int(x % uint64(n))
+}
+
+// mergeKey returns the key used to match t against the fields of another
+// type when merging.
+func (t *Type) mergeKey() string {
+	if t.Config != nil && t.Config.CanonicalizeKeys {
+		return canonicalKey(t.Key)
+	}
+	return t.Name
+}
+
+// clone returns a copy of t that merging into doesn't change t: its maps,
+// slices, fields and variants are copied too.
+func (t *Type) clone() *Type {
+	c := *t
+	c.Tags = copyMap(t.Tags)
+	c.Keys = copyCounts(t.Keys)
+	c.Observed = copyCounts(t.Observed)
+	c.Numbers = append([]float64(nil), t.Numbers...)
+	c.Values = append([]string(nil), t.Values...)
+	if t.Children != nil {
+		c.Children = make(Fields, len(t.Children))
+		for i, field := range t.Children {
+			c.Children[i] = field.clone()
+		}
+	}
+	if t.Variants != nil {
+		c.Variants = make([]*Type, len(t.Variants))
+		for i, variant := range t.Variants {
+			c.Variants[i] = variant.clone()
+		}
+	}
+	return &c
+}
+
+func copyMap(m map[string]string) map[string]string {
+	if m == nil {
+		return nil
+	}
+	c := make(map[string]string, len(m))
+	for k, v := range m {
+		c[k] = v
+	}
+	return c
+}
+
+func copyCounts(m map[string]int) map[string]int {
+	if m == nil {
+		return nil
+	}
+	c := make(map[string]int, len(m))
+	for k, v := range m {
+		c[k] = v
+	}
+	return c
+}
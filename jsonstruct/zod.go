@@ -0,0 +1,108 @@
+package jsonstruct
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// renderZod renders typ as a TypeScript module exporting a Zod schema named
+// name, along with the TypeScript type inferred from it. container is "[]"
+// or "map[string]" if the root JSON value is an array or map of typ.
+func renderZod(typ *Type, name, container string) []byte {
+	schema := zodSchema(typ, 0)
+	switch container {
+	case "[]":
+		schema = "z.array(" + schema + ")"
+	case "map[string]":
+		schema = "z.record(z.string(), " + schema + ")"
+	}
+	var b strings.Builder
+	b.WriteString("import { z } from \"zod\";\n\n")
+	fmt.Fprintf(&b, "export const %s = %s;\n\n", name, schema)
+	fmt.Fprintf(&b, "export type %s = z.infer<typeof %s>;\n", name, name)
+	return []byte(b.String())
+}
+
+// zodSchema returns the Zod schema for values of typ, indented for nesting
+// at the given depth.
+func zodSchema(typ *Type, depth int) string {
+	schema := zodElemSchema(typ, depth)
+	if typ.Map {
+		schema = "z.record(" + schema + ")"
+	}
+	for i := 0; i < typ.Repeated; i++ {
+		schema = "z.array(" + schema + ")"
+	}
+	return schema
+}
+
+// zodElemSchema returns the Zod schema for typ ignoring its slice dimensions.
+func zodElemSchema(typ *Type, depth int) string {
+	if len(typ.Variants) > 1 && typ.Config.PolymorphicField != "" {
+		var variants []string
+		for _, variant := range typ.Variants {
+			variants = append(variants, zodObject(variant, depth+1))
+		}
+		indent := strings.Repeat("  ", depth+1)
+		return fmt.Sprintf("z.discriminatedUnion(%q, [\n%s%s,\n%s])",
+			typ.Config.PolymorphicField, indent, strings.Join(variants, ",\n"+indent), strings.Repeat("  ", depth))
+	}
+	switch typ.Type {
+	case "struct":
+		return zodObject(typ, depth)
+	case "string", "net.IP", "time.Time", "[]byte":
+		return "z.string()"
+	case "float64", "json.Number":
+		return "z.number()"
+	case "int64", "int":
+		return "z.number().int()"
+	case "bool":
+		return "z.boolean()"
+	case "map[string]interface{}":
+		return "z.record(z.string(), z.unknown())"
+	}
+	// fall back to the single non-null type the value was observed as.
+	observed := typ.nonNullTypes()
+	switch {
+	case len(observed) == 0 && typ.Observed["null"] > 0:
+		return "z.null()"
+	case len(observed) == 1 && !strings.Contains(observed[0], "struct") && observed[0] != typ.Type:
+		base, _ := splitObserved(observed[0])
+		return zodElemSchema(&Type{Type: base, Config: typ.Config}, depth)
+	}
+	return "z.unknown()"
+}
+
+// zodObject returns a z.object schema for the struct type typ. Fields present
+// in only some of the objects are optional, and fields observed as null are
+// nullable.
+func zodObject(typ *Type, depth int) string {
+	if len(typ.Children) == 0 {
+		return "z.object({})"
+	}
+	indent := strings.Repeat("  ", depth+1)
+	var b strings.Builder
+	b.WriteString("z.object({\n")
+	for _, field := range typ.Children {
+		schema := zodSchema(field, depth+1)
+		if field.Observed["null"] > 0 && schema != "z.null()" {
+			schema += ".nullable()"
+		}
+		if field.Count < typ.objects() {
+			schema += ".optional()"
+		}
+		fmt.Fprintf(&b, "%s%s: %s,\n", indent, zodKey(field.Key), schema)
+	}
+	b.WriteString(strings.Repeat("  ", depth) + "})")
+	return b.String()
+}
+
+// zodKey returns key as a TypeScript property name, quoting it unless it is
+// a valid identifier.
+func zodKey(key string) string {
+	if identifier.MatchString(key) {
+		return key
+	}
+	return strconv.Quote(key)
+}
@@ -2,7 +2,8 @@
 
 // json-to-struct generates go struct defintions from JSON documents
 //
-// Reads from stdin and prints to stdout
+// Reads from stdin, or from the files named as arguments, and prints to
+// stdout. The records of multiple files are merged into one type.
 //
 // Example:
 // 	curl -s https://api.github.com/users/tmc | json-to-struct -name=User
@@ -18,22 +19,22 @@
 //  	CreatedAt         string      `json:"created_at,omitempty"`
 //  	Email             interface{} `json:"email,omitempty"`
 //  	EventsURL         string      `json:"events_url,omitempty"`
-//  	Followers         float64     `json:"followers,omitempty"`
+//  	Followers         int64       `json:"followers,omitempty"`
 //  	FollowersURL      string      `json:"followers_url,omitempty"`
-//  	Following         float64     `json:"following,omitempty"`
+//  	Following         int64       `json:"following,omitempty"`
 //  	FollowingURL      string      `json:"following_url,omitempty"`
 //  	GistsURL          string      `json:"gists_url,omitempty"`
 //  	GravatarID        string      `json:"gravatar_id,omitempty"`
 //  	Hireable          bool        `json:"hireable,omitempty"`
 //  	HtmlURL           string      `json:"html_url,omitempty"`
-//  	ID                float64     `json:"id,omitempty"`
+//  	ID                int64       `json:"id,omitempty"`
 //  	Location          string      `json:"location,omitempty"`
 //  	Login             string      `json:"login,omitempty"`
 //  	Name              string      `json:"name,omitempty"`
 //  	NodeID            string      `json:"node_id,omitempty"`
 //  	OrganizationsURL  string      `json:"organizations_url,omitempty"`
-//  	PublicGists       float64     `json:"public_gists,omitempty"`
-//  	PublicRepos       float64     `json:"public_repos,omitempty"`
+//  	PublicGists       int64       `json:"public_gists,omitempty"`
+//  	PublicRepos       int64       `json:"public_repos,omitempty"`
 //  	ReceivedEventsURL string      `json:"received_events_url,omitempty"`
 //  	ReposURL          string      `json:"repos_url,omitempty"`
 //  	SiteAdmin         bool        `json:"site_admin,omitempty"`
@@ -46,36 +47,380 @@
 package main
 
 import (
+	"bufio"
+	"bytes"
+	"errors"
 	"flag"
 	"fmt"
+	"io"
+	"io/ioutil"
 	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/tmc/json-to-struct/jsonstruct"
 )
 
 var (
 	flagName      = flag.String("name", "Foo", "the name of the struct")
 	flagPkg       = flag.String("pkg", "main", "the name of the package for the generated code")
 	flagOmitEmpty = flag.Bool("omitempty", true, "if true, emits struct field tags with 'omitempty'")
+	flagTagCase   = flag.String("tag-case", "original", "the case of json tag names: original, snake, camel or kebab")
+	flagRootAlias = flag.Bool("root-alias", false, "if true, a root JSON array is emitted as a named slice type")
+	flagTopMap    = flag.Bool("top-level-map", false, "if true, the root JSON object is emitted as a map of a single merged value type")
+	flagRare      = flag.Float64("mark-rare-deprecated", 0, "if positive, fields seen in less than this fraction of records are marked deprecated")
+	flagCanonical = flag.Bool("canonicalize-keys", false, "if true, keys differing only by case, underscores or hyphens are merged into one field")
+	flagGenCtor   = flag.Bool("gen-constructor", false, "if true, emits a NewName constructor that initializes slice fields to empty slices")
+	flagDetectIP  = flag.Bool("detect-ip", false, "if true, string fields holding only IP addresses are emitted as net.IP")
+	flagReport    = flag.String("merge-report", "", "if set, writes a summary of each field's presence and type decisions to this file, or to stderr if '-'")
+	flagPolyField = flag.String("polymorphic-field", "", "if set, arrays of objects are split into variant structs by this discriminator key")
+	flagTimeUnix  = flag.String("time-unix", "", "if 'seconds' or 'millis', annotates numeric fields holding Unix timestamps in that unit")
+	flagTagQuote  = flag.String("tag-quote", "backtick", "the quoting of struct tags: backtick or double")
+	flagArrDepth  = flag.Int("array-depth", jsonstruct.DefaultConfig.ArrayDepth, "the number of nested array dimensions to unwrap before falling back to interface{} elements, or 0 for no limit")
+	flagREPL      = flag.Bool("repl", false, "if true, reads one JSON document per line and prints a struct for each until EOF")
+	flagOrder     = flag.String("field-order", "alphabetical", "the order of struct fields: alphabetical, type-grouped (scalars, then arrays, then structs), first-record (the key order of the first object) or common-first-alpha (the most common fields first, then alphabetical)")
+	flagGenMarsh  = flag.Bool("gen-marshalers", false, "if true, emits a MarshalJSON method that encodes nil slice fields as [] instead of null")
+	flagSourceMap = flag.String("source-map", "", "if set, writes a JSON object mapping each Go field path to its JSON path to this file")
+	flagBodyOnly  = flag.Bool("body-only", false, "if true, emits only the type declarations, without the package clause and imports")
+	flagAnonymous = flag.Bool("anonymous", false, "if true, emits only the anonymous struct type, without a name; can't be combined with -enums, -flexible-types, -nullable, -detect-json-strings or -polymorphic-field")
+	flagRawFields = flag.String("raw-fields", "", "a comma-separated list of JSON keys whose fields are emitted as json.RawMessage")
+	flagRawOnConf = flag.Bool("raw-on-conflict", false, "if true, fields with conflicting types are emitted as json.RawMessage instead of interface{}")
+	flagLang      = flag.String("lang", "go", "the output language: go, zod (TypeScript Zod schemas), jsonschema (draft-07 JSON Schema) or proto (proto3 message)")
+	flagFormat    = flag.String("format", "json", "the input format: json, query (URL query strings, one per line), form (form-encoded bodies, one per line) or yaml")
+	flagJSONStr   = flag.Bool("detect-json-strings", false, "if true, string fields holding JSON objects or arrays are emitted as named types of the embedded JSON that decode from and encode to JSON strings")
+	flagNoAlign   = flag.Bool("no-tag-align", false, "if true, struct fields are separated by single spaces instead of being aligned in columns, for smaller diffs")
+	flagCanonForm = flag.Bool("canonical", false, "if true, emits the byte-stable canonical form intended for checked-in golden files: alphabetical fields and enum values, sorted backtick-quoted tags, aligned fields and no example comments, overriding -field-order, -no-tag-align, -tag-quote and -example-comments")
+	flagEmptyMap  = flag.Bool("empty-object-as-map", false, "if true, fields only ever seen as empty objects are emitted as map[string]interface{}")
+	flagNumFields = flag.String("json-number-fields", "", "a comma-separated list of JSON keys whose numeric fields are emitted as json.Number")
+	flagConsts    = flag.Bool("detect-constants", false, "if true, fields that held the same value in every record are annotated with a 'constant:' comment")
+	flagEmitConst = flag.Bool("emit-constants", false, "if true, fields that held the same value in every record are emitted as package constants instead of struct fields")
+	flagNullable  = flag.String("nullable", "", "if 'generic', fields that were null or missing are emitted with a generic Nullable[T] wrapper (requires Go 1.18)")
+	flagJSONPath  = flag.String("jsonpath", "", "if set, a JSONPath expression such as '$.results[*]' selecting the objects to infer from; supports the steps .name, ['name'], .*, [*] and [n], not recursive descent, filters, slices or unions")
+	flagScalarArr = flag.String("scalar-array", "any", "the type of fields seen both as a scalar and as an array of it: any (the conflict type) or widen (the array type)")
+	flagExample   = flag.String("gen-example", "", "if set, writes an example JSON document matching the generated type to this file")
+	flagNoNDJSON  = flag.Bool("no-ndjson", false, "if true, input must be a single JSON document; invalid input is reported instead of being decoded as NDJSON")
+	flagNullHeavy = flag.Float64("null-heavy-threshold", 0, "if positive, fields null in more than this fraction of records are emitted as pointers to their non-null type")
+	flagIntInfer  = flag.Bool("int-inference", jsonstruct.DefaultConfig.IntInference, "if true, numeric fields holding only integers are emitted as integers instead of float64")
+	flagIntType   = flag.String("int-type", "int64", "the type of integer fields: int64 or int")
+	flagRegistry  = flag.Bool("gen-registry", false, "if true, emits a TypeRegistry map from each generated type name to its reflect.Type")
+	flagLayout    = flag.String("layout", "root-first", "the order of declarations: root-first (the root type first) or leaf-first (each type after the types it refers to)")
+	flagPointers  = flag.Bool("pointers-for-optional", false, "if true, fields missing from some records or seen as null are emitted as pointers")
+	flagReqAll    = flag.Bool("require-all-present", false, "if true, fails if any field is missing from some records, unless listed in -optional")
+	flagOptional  = flag.String("optional", "", "a comma-separated list of JSON keys allowed to be missing with -require-all-present")
+	flagJSONNum   = flag.Bool("use-json-number", false, "if true, numbers are decoded losslessly and numeric fields are emitted as json.Number")
+	flagEmbedFile = flag.String("embed-sample-file", "", "if set, writes the input to this file, relative to the -o directory, and embeds it with //go:embed in a variable alongside the struct")
+	flagTags      = flag.String("tags", "json", "a comma-separated list of the struct tags to emit: json, yaml and bson")
+	flagKnown     = flag.String("known-types", "", "if set, a Go file or package directory whose struct types are referenced in place of matching nested structs")
+	flagOutput    = flag.String("o", "", "if set, writes the generated code to this file instead of stdout")
+	flagHistogram = flag.Bool("histogram", false, "if true, writes an ASCII histogram of each numeric field's values to stderr")
+	flagReservoir = flag.Int("reservoir-percentiles", 10000, "the number of values of each numeric field sampled for -histogram and its percentiles; larger samples are more accurate, smaller ones use less memory")
+	flagNamed     = flag.Bool("named-nested", false, "if true, nested objects are emitted as named struct types, named after their path, instead of inline")
+	flagInitials  = flag.String("initialisms", "", "a comma-separated list of words, in addition to common initialisms such as API and ID, upper cased in field names")
+	flagEnums     = flag.Int("enums", 0, "if positive, string and small integer fields with fewer than this many distinct values are emitted as a named type with a constant per value")
+	flagSmartOmit = flag.Bool("smart-omitempty", false, "if true, 'omitempty' is only emitted on fields missing from some records or seen as null")
+	flagFlexible  = flag.Bool("flexible-types", false, "if true, fields seen both as strings and numbers are emitted as a type that decodes either")
+	flagUnwrap    = flag.Bool("unwrap-singletons", false, "if true, fields that were always a one-element array are emitted as the element type")
+	flagMaxDepth  = flag.Int("max-depth", 0, "if positive, objects nested more than this many levels deep are emitted as map[string]interface{}")
+	flagMaps      = flag.Int("detect-maps", 0, "if positive, objects with at least this many ID-like keys whose values share a type are emitted as map[string]T")
+	flagExComment = flag.Bool("example-comments", false, "if true, each scalar field is followed by a comment holding a value observed for it")
+	flagBytes     = flag.Bool("detect-bytes", false, "if true, string fields holding only base64 of at least 16 characters are emitted as []byte")
+	flagNameCase  = flag.String("case", "go", "the case of field names: go, preserve (the JSON key, exported) or screaming-snake")
+	flagGenReset  = flag.Bool("gen-reset", false, "if true, a Reset method that zeroes every field is emitted for each generated struct")
+	flagHonest    = flag.Bool("honest-omitempty", false, "if true, 'omitempty' is only emitted on pointer, slice, map, string and interface fields")
+	flagValidate  = flag.String("validate-against", "", "if set, a Go file or package directory whose struct named by -name the input is checked against instead of generating code; differences are reported to stderr")
+	flagParallel  = flag.Int("parallel", 0, "if greater than 1, the number of goroutines inferring types from the records of large inputs")
+	flagSampleLim = flag.Int("sample-limit", 0, "if positive, types are inferred from only the first this many records, and no more input is read")
+	flagUUID      = flag.Bool("detect-uuid", false, "if true, string fields holding only UUIDs are emitted as the -uuid-type type")
+	flagUUIDType  = flag.String("uuid-type", "github.com/google/uuid.UUID", "the type of UUID fields with -detect-uuid, qualified by its import path")
+	flagBadLines  = flag.String("bad-lines", "", "if set, NDJSON lines that fail to parse are written to this file")
+	flagBench     = flag.Bool("bench", false, "if true, reports inference throughput and memory statistics to stderr instead of emitting code")
 )
 
+var flagTimeLayouts stringsFlag
+var flagStructDoc optionalStringFlag
+var flagFieldTypes stringsFlag
+
+func init() {
+	flag.Var(&flagTimeLayouts, "time-layout", "a time.Parse layout; string fields matching it are emitted as time.Time if it is RFC 3339, and commented with it otherwise (repeatable)")
+	flag.Var(&flagFieldTypes, "field-type", "a key=GoType mapping pinning the type of fields with that JSON key, such as id=uint64 or price=github.com/shopspring/decimal.Decimal (repeatable)")
+	flag.Var(&flagStructDoc, "struct-comment", "the doc comment of the top-level type; without a value, a minimal comment naming the type")
+}
+
+// stringsFlag is a flag.Value that collects the values of a repeated flag.
+type stringsFlag []string
+
+func (s *stringsFlag) String() string {
+	return strings.Join(*s, ",")
+}
+
+// optionalStringFlag is a flag.Value for a string flag that may be given
+// without a value.
+type optionalStringFlag struct {
+	set   bool
+	value string
+}
+
+func (f *optionalStringFlag) String() string {
+	return f.value
+}
+
+func (f *optionalStringFlag) Set(v string) error {
+	f.set = true
+	if v != "true" {
+		f.value = v
+	}
+	return nil
+}
+
+func (f *optionalStringFlag) IsBoolFlag() bool {
+	return true
+}
+
+func (s *stringsFlag) Set(v string) error {
+	*s = append(*s, v)
+	return nil
+}
+
 func main() {
 	flag.Parse()
 
-	if isInteractive() {
+	if isInteractive() && !*flagREPL && flag.NArg() == 0 {
 		flag.Usage()
 		fmt.Fprintln(os.Stderr, "Expects input on stdin")
 		os.Exit(1)
 	}
+	if err := run(os.Stdin, os.Stdout, os.Stderr); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
 
-	cfg := &Config{}
-	*cfg = DefaultConfig
+// createFile creates the named output file. Tests replace it to observe the
+// files written.
+var createFile = func(name string) (io.WriteCloser, error) {
+	return os.Create(name)
+}
+
+// run generates the output the flags ask for from stdin, or from the files
+// named as arguments, writing it to stdout and diagnostics to stderr. The
+// files it opens are closed before it returns, and an error closing them is
+// returned like any other.
+func run(stdin io.Reader, stdout, stderr io.Writer) (err error) {
+	var closers []io.Closer
+	defer func() {
+		for _, c := range closers {
+			if cerr := c.Close(); cerr != nil && err == nil {
+				err = cerr
+			}
+		}
+	}()
+	create := func(name string) (io.WriteCloser, error) {
+		f, err := createFile(name)
+		if err == nil {
+			closers = append(closers, f)
+		}
+		return f, err
+	}
+
+	opts := jsonstruct.Options{Name: *flagName, Package: *flagPkg, Config: jsonstruct.DefaultConfig}
+	cfg := &opts.Config
 	cfg.OmitEmpty = *flagOmitEmpty
+	cfg.TagCase = *flagTagCase
+	cfg.RootAlias = *flagRootAlias
+	cfg.TopLevelMap = *flagTopMap
+	cfg.RareDeprecated = *flagRare
+	cfg.CanonicalizeKeys = *flagCanonical
+	cfg.GenConstructor = *flagGenCtor
+	cfg.DetectIP = *flagDetectIP
+	cfg.PolymorphicField = *flagPolyField
+	cfg.TimeLayouts = flagTimeLayouts
+	cfg.TimeUnix = *flagTimeUnix
+	cfg.TagQuote = *flagTagQuote
+	cfg.ArrayDepth = *flagArrDepth
+	cfg.FieldOrder = *flagOrder
+	cfg.GenMarshalers = *flagGenMarsh
+	cfg.BodyOnly = *flagBodyOnly
+	cfg.Anonymous = *flagAnonymous
+	if *flagRawFields != "" {
+		cfg.RawFields = strings.Split(*flagRawFields, ",")
+	}
+	cfg.RawOnConflict = *flagRawOnConf
+	if *flagNumFields != "" {
+		cfg.JSONNumberFields = strings.Split(*flagNumFields, ",")
+	}
+	cfg.Lang = *flagLang
+	cfg.Format = *flagFormat
+	cfg.DetectJSONStrings = *flagJSONStr
+	cfg.NoTagAlign = *flagNoAlign
+	cfg.Canonical = *flagCanonForm
+	cfg.EmptyObjectAsMap = *flagEmptyMap
+	cfg.DetectConstants = *flagConsts
+	cfg.EmitConstants = *flagEmitConst
+	cfg.Nullable = *flagNullable
+	cfg.JSONPath = *flagJSONPath
+	cfg.ScalarArray = *flagScalarArr
+	cfg.NoNDJSON = *flagNoNDJSON
+	cfg.NullHeavy = *flagNullHeavy
+	cfg.IntInference = *flagIntInfer
+	cfg.IntType = *flagIntType
+	cfg.GenRegistry = *flagRegistry
+	cfg.Layout = *flagLayout
+	cfg.PointersForOptional = *flagPointers
+	cfg.RequireAllPresent = *flagReqAll
+	if *flagOptional != "" {
+		cfg.Optional = strings.Split(*flagOptional, ",")
+	}
+	cfg.UseJSONNumber = *flagJSONNum
+	cfg.Tags = strings.Split(*flagTags, ",")
+	if *flagHistogram {
+		cfg.Histogram = stderr
+	}
+	cfg.NumberSample = *flagReservoir
+	cfg.NamedNested = *flagNamed
+	if *flagInitials != "" {
+		cfg.Initialisms = strings.Split(*flagInitials, ",")
+	}
+	cfg.Enums = *flagEnums
+	cfg.SmartOmitEmpty = *flagSmartOmit
+	cfg.FlexibleTypes = *flagFlexible
+	cfg.UnwrapSingletons = *flagUnwrap
+	cfg.MaxDepth = *flagMaxDepth
+	cfg.DetectMaps = *flagMaps
+	cfg.ExampleComments = *flagExComment
+	cfg.DetectBytes = *flagBytes
+	cfg.NameCase = *flagNameCase
+	cfg.GenReset = *flagGenReset
+	cfg.HonestOmitEmpty = *flagHonest
+	cfg.Parallel = *flagParallel
+	cfg.SampleLimit = *flagSampleLim
+	cfg.DetectUUID = *flagUUID
+	cfg.UUIDType = *flagUUIDType
+	if *flagEmbedFile != "" {
+		cfg.EmbedSample = filepath.ToSlash(*flagEmbedFile)
+	}
+	for _, mapping := range flagFieldTypes {
+		i := strings.Index(mapping, "=")
+		if i < 0 {
+			return fmt.Errorf("invalid -field-type %q: want key=GoType", mapping)
+		}
+		if cfg.FieldTypes == nil {
+			cfg.FieldTypes = map[string]string{}
+		}
+		cfg.FieldTypes[mapping[:i]] = mapping[i+1:]
+	}
+	if flagStructDoc.set {
+		cfg.StructComment = flagStructDoc.value
+		if cfg.StructComment == "" {
+			cfg.StructComment = *flagName + " was generated from JSON."
+		}
+	}
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+	cfg.Log = stderr
+	if *flagSourceMap != "" {
+		f, err := create(*flagSourceMap)
+		if err != nil {
+			return fmt.Errorf("error creating source map file: %w", err)
+		}
+		cfg.SourceMap = f
+	}
+	if *flagExample != "" {
+		f, err := create(*flagExample)
+		if err != nil {
+			return fmt.Errorf("error creating example file: %w", err)
+		}
+		cfg.Example = f
+	}
+	if *flagKnown != "" {
+		known, err := jsonstruct.LoadKnownTypes(*flagKnown)
+		if err != nil {
+			return fmt.Errorf("error loading known types: %w", err)
+		}
+		cfg.KnownTypes = known
+	}
+	if cfg.EmbedSample != "" {
+		// the embed path is relative to the generated file.
+		f, err := create(filepath.Join(filepath.Dir(*flagOutput), filepath.FromSlash(cfg.EmbedSample)))
+		if err != nil {
+			return fmt.Errorf("error creating sample file: %w", err)
+		}
+		cfg.Sample = f
+	}
+	if *flagReport == "-" {
+		cfg.MergeReport = stderr
+	} else if *flagReport != "" {
+		f, err := create(*flagReport)
+		if err != nil {
+			return fmt.Errorf("error creating merge report file: %w", err)
+		}
+		cfg.MergeReport = f
+	}
+	if *flagBadLines != "" {
+		f, err := create(*flagBadLines)
+		if err != nil {
+			return fmt.Errorf("error creating bad lines file: %w", err)
+		}
+		cfg.BadLines = f
+	}
 
-	if output, err := generate(os.Stdin, *flagName, *flagPkg, cfg); err != nil {
-		fmt.Fprintln(os.Stderr, "error parsing", err)
-		os.Exit(1)
-	} else {
-		fmt.Print(string(output))
+	var input io.Reader = stdin
+	if flag.NArg() > 0 {
+		r, err := readFiles(flag.Args())
+		if err != nil {
+			return fmt.Errorf("error reading input: %w", err)
+		}
+		closers = append(closers, r)
+		input = r
+	}
+
+	if *flagREPL && *flagOutput != "" {
+		return errors.New("-o can't be combined with -repl")
+	}
+	if *flagREPL {
+		if err := runREPL(input, stdout, stderr, opts); err != nil {
+			return fmt.Errorf("error reading input: %w", err)
+		}
+		return nil
+	}
+
+	if *flagValidate != "" {
+		n, err := jsonstruct.CheckDrift(stderr, input, *flagValidate, opts)
+		if err != nil {
+			return fmt.Errorf("error checking drift: %w", err)
+		}
+		if n > 0 {
+			return fmt.Errorf("found %d differences from %s", n, *flagValidate)
+		}
+		return nil
+	}
+
+	if *flagBench {
+		if err := runBenchmark(stderr, input, opts); err != nil {
+			return fmt.Errorf("error parsing: %w", err)
+		}
+		return nil
+	}
+
+	var output bytes.Buffer
+	if err := jsonstruct.Generate(&output, input, opts); err != nil {
+		return fmt.Errorf("error parsing: %w", err)
+	}
+	if *flagOutput == "" {
+		_, err := stdout.Write(output.Bytes())
+		return err
 	}
+	f, err := create(*flagOutput)
+	if err != nil {
+		return fmt.Errorf("error creating output file: %w", err)
+	}
+	if _, err := f.Write(output.Bytes()); err != nil {
+		return fmt.Errorf("error writing output: %w", err)
+	}
+	fmt.Fprintf(stderr, "wrote %d bytes to %s\n", output.Len(), *flagOutput)
+	return nil
 }
 
 // Return true if os.Stdin appears to be interactive
@@ -86,3 +431,61 @@ func isInteractive() bool {
 	}
 	return fileInfo.Mode()&(os.ModeCharDevice|os.ModeCharDevice) != 0
 }
+
+// readFiles returns the named files as a single input, to be closed by the
+// caller. A single file is returned as is. The records of multiple files are
+// decoded file by file and merged into one type.
+func readFiles(paths []string) (io.ReadCloser, error) {
+	if len(paths) > 1 {
+		return jsonstruct.OpenFiles(paths...)
+	}
+	data, err := ioutil.ReadFile(paths[0])
+	if err != nil {
+		return nil, err
+	}
+	return ioutil.NopCloser(bytes.NewReader(data)), nil
+}
+
+// runREPL generates and writes a struct to out for each line of input until
+// EOF. Lines that fail to parse are reported to errOut and skipped.
+func runREPL(input io.Reader, out, errOut io.Writer, opts jsonstruct.Options) error {
+	scanner := bufio.NewScanner(input)
+	scanner.Buffer(nil, 64<<20)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		if err := jsonstruct.Generate(out, bytes.NewReader(line), opts); err != nil {
+			fmt.Fprintln(errOut, "error parsing", err)
+		}
+	}
+	return scanner.Err()
+}
+
+// runBenchmark runs generate over input and writes timing and memory
+// statistics to w. The generated code is discarded.
+func runBenchmark(w io.Writer, input io.Reader, opts jsonstruct.Options) error {
+	data, err := ioutil.ReadAll(input)
+	if err != nil {
+		return err
+	}
+	var before, after runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+	start := time.Now()
+	var count int
+	opts.Records = &count
+	if err := jsonstruct.Generate(ioutil.Discard, bytes.NewReader(data), opts); err != nil {
+		return err
+	}
+	elapsed := time.Since(start)
+	runtime.ReadMemStats(&after)
+
+	seconds := elapsed.Seconds()
+	fmt.Fprintf(w, "input:    %d bytes, %d records\n", len(data), count)
+	fmt.Fprintf(w, "elapsed:  %v\n", elapsed)
+	fmt.Fprintf(w, "rate:     %.0f records/sec, %.2f MB/sec\n", float64(count)/seconds, float64(len(data))/seconds/1e6)
+	fmt.Fprintf(w, "allocs:   %d (%d bytes)\n", after.Mallocs-before.Mallocs, after.TotalAlloc-before.TotalAlloc)
+	return nil
+}
@@ -5,14 +5,18 @@ package main
 import (
 	"strings"
 	"syscall/js"
+
+	"github.com/tmc/json-to-struct/jsonstruct"
 )
 
 func jsonToStructFunction(this js.Value, p []js.Value) interface{} {
 	in := strings.NewReader(p[0].String())
-	if output, err := generate(in, "Type", "main", &DefaultConfig); err != nil {
+	var output strings.Builder
+	opts := jsonstruct.Options{Name: "Type", Package: "main", Config: jsonstruct.DefaultConfig}
+	if err := jsonstruct.Generate(&output, in, opts); err != nil {
 		return js.ValueOf(err.Error())
 	} else {
-		return js.ValueOf(string(output))
+		return js.ValueOf(output.String())
 	}
 	return js.Null()
 }
@@ -0,0 +1,148 @@
+// +build !js
+
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// setFlags resets every command flag to its default and then parses args.
+func setFlags(t *testing.T, args []string) {
+	flag.VisitAll(func(f *flag.Flag) {
+		if !strings.HasPrefix(f.Name, "test.") {
+			f.Value.Set(f.DefValue)
+		}
+	})
+	flagTimeLayouts, flagFieldTypes, flagStructDoc = nil, nil, optionalStringFlag{}
+	if err := flag.CommandLine.Parse(args); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// failingCloser is an output file whose Close fails.
+type failingCloser struct {
+	bytes.Buffer
+}
+
+func (*failingCloser) Close() error {
+	return errors.New("disk full")
+}
+
+func TestRun(t *testing.T) {
+	dir, err := ioutil.TempDir("", "json-to-struct")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	output := filepath.Join(dir, "foo.go")
+
+	tests := []struct {
+		name       string
+		args       []string
+		stdin      string
+		failClose  bool
+		wantStdout []string
+		wantStderr []string
+		wantErr    string
+	}{
+		{
+			name:       "repl recovers from bad lines",
+			args:       []string{"-repl"},
+			stdin:      "{\"a\":1}\nnot json\n\n{\"b\":\"x\"}\n",
+			wantStdout: []string{"A int64 ", "B string "},
+			wantStderr: []string{"error parsing"},
+		},
+		{
+			name:    "repl rejects -o",
+			args:    []string{"-repl", "-o", output},
+			stdin:   "{\"a\":1}\n",
+			wantErr: "-o can't be combined with -repl",
+		},
+		{
+			name:       "output file",
+			args:       []string{"-o", output},
+			stdin:      `{"a":1}`,
+			wantStderr: []string{"wrote ", output},
+		},
+		{
+			name:      "output file close error",
+			args:      []string{"-o", output},
+			stdin:     `{"a":1}`,
+			failClose: true,
+			wantErr:   "disk full",
+		},
+		{
+			name:      "report file close error",
+			args:      []string{"-merge-report", filepath.Join(dir, "report.txt")},
+			stdin:     `{"a":1}`,
+			failClose: true,
+			wantErr:   "disk full",
+		},
+		{
+			name:       "multiple files",
+			args:       []string{"jsonstruct/testdata/test_sample_limit_pretty.json", "jsonstruct/testdata/test_tag_case.json"},
+			wantStdout: []string{"Owner ", "FirstName ", "HTTPStatus "},
+		},
+		{
+			name:    "missing file",
+			args:    []string{"jsonstruct/testdata/test_simple_json.json", filepath.Join(dir, "missing.json")},
+			wantErr: "error reading input",
+		},
+		{
+			name:       "bench",
+			args:       []string{"-bench"},
+			stdin:      "{\"a\":1}\n{\"a\":2}\n{\"a\":3}\n",
+			wantStderr: []string{"3 records"},
+		},
+	}
+	defer func(create func(string) (io.WriteCloser, error)) { createFile = create }(createFile)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setFlags(t, tt.args)
+			createFile = func(name string) (io.WriteCloser, error) {
+				if tt.failClose {
+					return &failingCloser{}, nil
+				}
+				return os.Create(name)
+			}
+			var stdout, stderr bytes.Buffer
+			err := run(strings.NewReader(tt.stdin), &stdout, &stderr)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("run() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("run() error = %v", err)
+			}
+			for _, want := range tt.wantStdout {
+				if !strings.Contains(stdout.String(), want) {
+					t.Errorf("stdout missing %q:\n%s", want, stdout.String())
+				}
+			}
+			for _, want := range tt.wantStderr {
+				if !strings.Contains(stderr.String(), want) {
+					t.Errorf("stderr missing %q:\n%s", want, stderr.String())
+				}
+			}
+		})
+	}
+
+	// the output file of the "output file" case.
+	got, err := ioutil.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(got), "A int64 ") {
+		t.Errorf("output file = %s, want field A int64", got)
+	}
+}